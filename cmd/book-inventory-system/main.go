@@ -8,17 +8,27 @@ import (
 	logger "book-inventory-system/pkg/logger"
 	"context"
 	"flag"
+	"fmt"
 	"go.uber.org/zap"
 	"log"
+	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 )
 
 func main() {
+	// "validate" as the first argument only checks the dumps for integrity
+	// violations, prints them and exits with status 1 if there are any.
+	args := os.Args[1:]
+	validate := len(args) > 0 && args[0] == "validate"
+	if validate {
+		args = args[1:]
+	}
+
 	outputFileLogPath := flag.String("logfilepath", "", "output log filepath")
 	cfgFilePath := flag.String("cfgfilepath", "", "cfg file path")
-	flag.Parse()
+	_ = flag.CommandLine.Parse(args)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -55,7 +65,29 @@ func main() {
 
 	l.Info("init config")
 
+	// validating the dumps changes nothing, so there is nothing to audit
+	auditLog := cfg.AuditLog
+	if validate {
+		auditLog = ""
+	}
+
 	r, err := repository.New(
+		repository.WithLoanPeriod(cfg.LoanPeriod),
+		repository.WithDefaultCategories(
+			cfg.LendingPolicy.DefaultReaderCategory,
+			cfg.LendingPolicy.DefaultItemCategory,
+		),
+		repository.WithRenewals(cfg.MaxRenewals, cfg.RenewalGracePeriod),
+		repository.WithHoldPickupWindow(cfg.HoldPickupWindow),
+		repository.WithSessions(cfg.SessionTTL, cfg.SessionIdleTimeout),
+		repository.WithFines(cfg.FineDailyRate, cfg.FineCap, cfg.FineBlockThreshold),
+		repository.WithBranchDump(cfg.Branches, cfg.DefaultBranchID),
+		repository.WithFunds(cfg.Funds),
+		repository.WithDeleteRules(cfg.DeleteRules),
+		repository.WithSeriesDump(cfg.Series),
+		repository.WithStrictValidation(cfg.StrictValidation && !validate),
+		repository.WithAuditLog(auditLog),
+		repository.WithReadOnlyDumps(validate),
 		repository.WithDump(
 			cfg.Admins,
 			cfg.Authors,
@@ -70,7 +102,26 @@ func main() {
 	)
 
 	if err != nil {
-		l.Fatal("failed to initialize repository: %v", err)
+		l.Fatalf("failed to initialize repository: %v", err)
+	}
+
+	violations := r.Violations()
+	if validate {
+		for _, violation := range violations {
+			fmt.Println(violation)
+		}
+
+		if len(violations) > 0 {
+			cancel()
+			os.Exit(1)
+		}
+
+		l.Info("no integrity violations")
+		return
+	}
+
+	for _, violation := range violations {
+		l.Warnf("integrity violation: %s", violation)
 	}
 
 	l.Info("init dump/service")
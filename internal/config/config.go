@@ -2,6 +2,7 @@ package book_inventory_system_config
 
 import (
 	"github.com/ilyakaznacheev/cleanenv"
+	"time"
 )
 
 type Config struct {
@@ -15,6 +16,70 @@ type Config struct {
 	Productions   string `yaml:"productions"`
 	Readers       string `yaml:"readers"`
 	Users         string `yaml:"users"`
+	Branches      string `yaml:"branches"`
+	Series        string `yaml:"series"`
+
+	// AuditLog is the file privileged changes are recorded in. Empty
+	// disables the audit log.
+	AuditLog string `yaml:"audit_log"`
+
+	DefaultBranchID int `yaml:"default_branch_id" env-default:"1"`
+
+	// StrictValidation refuses to start when the dumps have integrity
+	// violations instead of only logging them.
+	StrictValidation bool `yaml:"strict_validation" env-default:"false"`
+
+	// TokenSecret is the key bearer tokens are signed with, best set through
+	// the TOKEN_SECRET environment variable. Empty disables bearer tokens.
+	TokenSecret string        `yaml:"token_secret" env:"TOKEN_SECRET"`
+	TokenTTL    time.Duration `yaml:"token_ttl" env-default:"1h"`
+
+	SessionTTL         time.Duration `yaml:"session_ttl" env-default:"24h"`
+	SessionIdleTimeout time.Duration `yaml:"session_idle_timeout" env-default:"30m"`
+
+	LoanPeriod       time.Duration `yaml:"loan_period" env-default:"336h"`
+	HoldPickupWindow time.Duration `yaml:"hold_pickup_window" env-default:"72h"`
+
+	MaxRenewals        int           `yaml:"max_renewals" env-default:"2"`
+	RenewalGracePeriod time.Duration `yaml:"renewal_grace_period" env-default:"48h"`
+
+	FineDailyRate      int `yaml:"fine_daily_rate" env-default:"1000"`
+	FineCap            int `yaml:"fine_cap" env-default:"50000"`
+	FineBlockThreshold int `yaml:"fine_block_threshold" env-default:"10000"`
+
+	LendingPolicy LendingPolicy `yaml:"lending_policy"`
+
+	// Funds maps the name of an acquisitions fund to its budget in minor
+	// currency units.
+	Funds map[string]int `yaml:"funds"`
+
+	// DeleteRules sets what deleting a catalog entity does to the records
+	// referring to it, per relation: restrict, cascade or archive.
+	DeleteRules map[string]string `yaml:"delete_rules"`
+}
+
+// LendingPolicy maps a reader category and an item category to a lending
+// rule. A category of "*" in a rule matches any category, exact matches win.
+type LendingPolicy struct {
+	DefaultReaderCategory string        `yaml:"default_reader_category" env-default:"standard"`
+	DefaultItemCategory   string        `yaml:"default_item_category" env-default:"standard"`
+	Rules                 []LendingRule `yaml:"rules"`
+}
+
+// LendingRule sets the limits of one reader and item category combination.
+// MaxLoans is the number of items of the category a reader may hold at once,
+// zero makes the items not loanable. A zero LoanPeriod falls back to the
+// global loan_period, and an omitted MaxRenewals, FineDailyRate or FineCap to
+// the global max_renewals, fine_daily_rate and fine_cap, while an explicit
+// zero stands.
+type LendingRule struct {
+	ReaderCategory string        `yaml:"reader_category"`
+	ItemCategory   string        `yaml:"item_category"`
+	MaxLoans       int           `yaml:"max_loans"`
+	LoanPeriod     time.Duration `yaml:"loan_period"`
+	MaxRenewals    *int          `yaml:"max_renewals"`
+	FineDailyRate  *int          `yaml:"fine_daily_rate"`
+	FineCap        *int          `yaml:"fine_cap"`
 }
 
 func New(cfgPath string) (*Config, error) {
@@ -0,0 +1,52 @@
+package book_inventory_system_config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLendingRuleOmittedFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+lending_policy:
+  rules:
+    - reader_category: "standard"
+      item_category: "standard"
+      max_loans: 5
+    - reader_category: "staff"
+      item_category: "standard"
+      max_loans: 10
+      max_renewals: 0
+      fine_daily_rate: 0
+      fine_cap: 0
+`), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if len(cfg.LendingPolicy.Rules) != 2 {
+		t.Fatalf("loaded %d rules, want 2", len(cfg.LendingPolicy.Rules))
+	}
+
+	omitted := cfg.LendingPolicy.Rules[0]
+	if omitted.MaxRenewals != nil || omitted.FineDailyRate != nil || omitted.FineCap != nil {
+		t.Errorf("omitted fields are set: %+v", omitted)
+	}
+
+	zero := cfg.LendingPolicy.Rules[1]
+	for name, field := range map[string]*int{
+		"max_renewals":    zero.MaxRenewals,
+		"fine_daily_rate": zero.FineDailyRate,
+		"fine_cap":        zero.FineCap,
+	} {
+		if field == nil || *field != 0 {
+			t.Errorf("explicit zero %s = %v, want 0", name, field)
+		}
+	}
+}
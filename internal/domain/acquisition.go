@@ -0,0 +1,56 @@
+package book_inventory_system_domain
+
+import "time"
+
+// FundMapField is an acquisitions budget. Amounts are in minor currency units,
+// the fund is debited when an order is sent and credited back with the
+// unreceived part of an order when it is cancelled.
+type FundMapField struct {
+	Name    string `json:"name"`
+	Budget  int    `json:"budget"`
+	Debited int    `json:"debited"`
+	Balance int    `json:"balance"`
+}
+
+const (
+	OrderDraft             = "draft"
+	OrderSent              = "sent"
+	OrderPartiallyReceived = "partially_received"
+	OrderReceived          = "received"
+	OrderCancelled         = "cancelled"
+)
+
+// OrderMapField is a purchase order of new copies from a production. Lines can
+// only be added while the order is a draft.
+type OrderMapField struct {
+	OrderID      int         `json:"order_id"`
+	ProductionID int         `json:"production_id"`
+	Fund         string      `json:"fund"`
+	AdminID      int         `json:"admin_id"`
+	Status       string      `json:"status"`
+	Lines        []OrderLine `json:"lines"`
+	Total        int         `json:"total"`
+	CreateDate   time.Time   `json:"create_date"`
+	SendDate     *time.Time  `json:"send_date,omitempty"`
+	CloseDate    *time.Time  `json:"close_date,omitempty"`
+}
+
+// OrderLine orders Quantity copies of a book. InstanceIDs lists the copies
+// created for the received part of the line.
+type OrderLine struct {
+	BookID      int   `json:"book_id"`
+	Quantity    int   `json:"quantity"`
+	UnitPrice   int   `json:"unit_price"`
+	Received    int   `json:"received"`
+	InstanceIDs []int `json:"instance_ids,omitempty"`
+}
+
+// Outstanding returns the price of the copies that have not been received.
+func (o OrderMapField) Outstanding() int {
+	outstanding := 0
+	for _, line := range o.Lines {
+		outstanding += (line.Quantity - line.Received) * line.UnitPrice
+	}
+
+	return outstanding
+}
@@ -0,0 +1,9 @@
+package book_inventory_system_domain
+
+// Actor is the authenticated admin performing a privileged operation
+// together with the id of the request it came with, both recorded in the
+// audit log.
+type Actor struct {
+	Principal
+	RequestID string
+}
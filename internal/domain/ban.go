@@ -0,0 +1,24 @@
+package book_inventory_system_domain
+
+import "time"
+
+const (
+	BanActive  = "active"
+	BanExpired = "expired"
+	BanLifted  = "lifted"
+)
+
+// BanMapField is a ban of a user account. A ban without an end date lasts
+// until it is lifted.
+type BanMapField struct {
+	BanID       int        `json:"ban_id"`
+	UserID      int        `json:"user_id"`
+	AdminID     int        `json:"admin_id"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	LiftAdminID int        `json:"lift_admin_id,omitempty"`
+	LiftReason  string     `json:"lift_reason,omitempty"`
+	LiftDate    *time.Time `json:"lift_date,omitempty"`
+}
@@ -0,0 +1,55 @@
+package book_inventory_system_domain
+
+const (
+	BookHardcover = "hardcover"
+	BookPaperback = "paperback"
+	BookEbook     = "ebook"
+	BookAudiobook = "audiobook"
+)
+
+// IsValidBookFormat reports whether format is one of the known book formats.
+// An empty format means the format is unknown and is valid too.
+func IsValidBookFormat(format string) bool {
+	switch format {
+	case "", BookHardcover, BookPaperback, BookEbook, BookAudiobook:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleEditor      = "editor"
+	AuthorRoleTranslator  = "translator"
+	AuthorRoleIllustrator = "illustrator"
+)
+
+// BookAuthor credits an author of the book with a role.
+type BookAuthor struct {
+	AuthorID int    `json:"author_id"`
+	Role     string `json:"role"`
+}
+
+func IsValidAuthorRole(role string) bool {
+	switch role {
+	case AuthorRoleAuthor, AuthorRoleEditor, AuthorRoleTranslator, AuthorRoleIllustrator:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAuthoringRole reports whether the role makes the author a creator of the
+// book. Editors and translators work on someone else's text, so their books
+// are not counted as published by them.
+func IsAuthoringRole(role string) bool {
+	return role == AuthorRoleAuthor || role == AuthorRoleIllustrator
+}
+
+// BookEntry is a book together with its id, for lookups that do not start
+// from the id.
+type BookEntry struct {
+	BookID int `json:"book_id"`
+	BookMapField
+}
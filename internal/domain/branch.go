@@ -0,0 +1,40 @@
+package book_inventory_system_domain
+
+import "time"
+
+type Branch struct {
+	Branches []struct {
+		BranchID int    `json:"branch_id"`
+		Name     string `json:"name"`
+		Address  string `json:"address"`
+	} `json:"branches"`
+}
+
+type BranchMapField struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+const (
+	TransferRequested = "requested"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+// TransferMapField moves a copy between branches. A transfer requested by a
+// reader ends with the copy on the hold shelf of the destination branch, one
+// without a reader returns a copy to its home branch.
+type TransferMapField struct {
+	TransferID   int        `json:"transfer_id"`
+	InstanceID   int        `json:"instance_id"`
+	BookID       int        `json:"book_id"`
+	FromBranchID int        `json:"from_branch_id"`
+	ToBranchID   int        `json:"to_branch_id"`
+	ReaderID     int        `json:"reader_id,omitempty"`
+	Status       string     `json:"status"`
+	RequestDate  time.Time  `json:"request_date"`
+	DispatchDate *time.Time `json:"dispatch_date,omitempty"`
+	ReceiveDate  *time.Time `json:"receive_date,omitempty"`
+	CancelDate   *time.Time `json:"cancel_date,omitempty"`
+}
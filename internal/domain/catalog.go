@@ -0,0 +1,47 @@
+package book_inventory_system_domain
+
+// CatalogFilter selects books for the faceted catalog. A book matches a facet
+// when it has any of the listed ids, and matches the filter when it matches
+// every facet that lists ids. A nil Available does not filter availability.
+// BranchID scopes availability to a branch, 0 is the whole library.
+type CatalogFilter struct {
+	GenreIDs      []int
+	LanguageIDs   []int
+	ProductionIDs []int
+	AuthorIDs     []int
+	Available     *bool
+	BranchID      int
+}
+
+type CatalogBook struct {
+	BookEntry
+	Availability BookAvailability `json:"availability"`
+}
+
+// CatalogListing holds the matching books and the facet counts. The counts of
+// a facet are taken over the books matching all the other facets, so that
+// they tell how many books a change of the facet would give.
+type CatalogListing struct {
+	Total  int           `json:"total"`
+	Books  []CatalogBook `json:"books"`
+	Facets CatalogFacets `json:"facets"`
+}
+
+type CatalogFacets struct {
+	Genres       []FacetCount      `json:"genres"`
+	Languages    []FacetCount      `json:"languages"`
+	Productions  []FacetCount      `json:"productions"`
+	Authors      []FacetCount      `json:"authors"`
+	Availability AvailabilityFacet `json:"availability"`
+}
+
+type FacetCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type AvailabilityFacet struct {
+	Available   int `json:"available"`
+	Unavailable int `json:"unavailable"`
+}
@@ -0,0 +1,93 @@
+package book_inventory_system_domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeleteRule says what deleting an entity does to the records referring to
+// it through a relation. Restrict refuses the deletion, cascade deletes or
+// cancels the referring records, and archive keeps the entity, marked as
+// archived, so that the references stay valid.
+type DeleteRule string
+
+const (
+	DeleteRestrict DeleteRule = "restrict"
+	DeleteCascade  DeleteRule = "cascade"
+	DeleteArchive  DeleteRule = "archive"
+)
+
+// Relations an entity can be referred to through, named after the entity and
+// the referring records.
+const (
+	RelationBookInstances     = "book.instances"
+	RelationBookHolds         = "book.holds"
+	RelationBookOrders        = "book.orders"
+	RelationAuthorBooks       = "author.books"
+	RelationGenreBooks        = "genre.books"
+	RelationLanguageBooks     = "language.books"
+	RelationProductionBooks   = "production.books"
+	RelationProductionAuthors = "production.authors"
+	RelationProductionOrders  = "production.orders"
+)
+
+// DeleteRules lists the rules each relation allows, the first being the
+// default. Cascading is only offered where the referring records can go
+// without losing anything but the deleted entity itself.
+var DeleteRules = map[string][]DeleteRule{
+	RelationBookInstances:     {DeleteRestrict, DeleteCascade, DeleteArchive},
+	RelationBookHolds:         {DeleteRestrict, DeleteCascade, DeleteArchive},
+	RelationBookOrders:        {DeleteRestrict, DeleteArchive},
+	RelationAuthorBooks:       {DeleteRestrict, DeleteCascade, DeleteArchive},
+	RelationGenreBooks:        {DeleteRestrict, DeleteArchive},
+	RelationLanguageBooks:     {DeleteRestrict, DeleteArchive},
+	RelationProductionBooks:   {DeleteRestrict, DeleteArchive},
+	RelationProductionAuthors: {DeleteRestrict, DeleteArchive},
+	RelationProductionOrders:  {DeleteRestrict, DeleteArchive},
+}
+
+// Reference is a record taking part in a deletion: one that blocks it, or one
+// that was deleted, archived or cancelled by it.
+type Reference struct {
+	Relation string `json:"relation,omitempty"`
+	Entity   string `json:"entity"`
+	ID       int    `json:"id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (r Reference) String() string {
+	s := fmt.Sprintf("%s %d", r.Entity, r.ID)
+	if r.Relation != "" {
+		s += " via " + r.Relation
+	}
+
+	if r.Reason != "" {
+		s += " (" + r.Reason + ")"
+	}
+
+	return s
+}
+
+// ConflictError is returned when references block a deletion. Nothing has
+// been changed when it is returned.
+type ConflictError struct {
+	Entity     string      `json:"entity"`
+	ID         int         `json:"id"`
+	References []Reference `json:"references"`
+}
+
+func (e *ConflictError) Error() string {
+	references := make([]string, 0, len(e.References))
+	for _, reference := range e.References {
+		references = append(references, reference.String())
+	}
+
+	return fmt.Sprintf("cannot delete %s %d, it is referenced by %s", e.Entity, e.ID, strings.Join(references, ", "))
+}
+
+// DeleteResult lists everything a deletion changed.
+type DeleteResult struct {
+	Deleted   []Reference `json:"deleted"`
+	Archived  []Reference `json:"archived,omitempty"`
+	Cancelled []Reference `json:"cancelled,omitempty"`
+}
@@ -1,5 +1,9 @@
 package book_inventory_system_domain
 
+import (
+	"github.com/goccy/go-json"
+)
+
 type User struct {
 	Users []struct {
 		UserID       int    `json:"user_id,omitempty"`
@@ -18,8 +22,9 @@ type Admin struct {
 
 type Reader struct {
 	Readers []struct {
-		ReaderID   int   `json:"reader_id"`
-		InstanceID []int `json:"instance_id"`
+		ReaderID   int    `json:"reader_id"`
+		InstanceID []int  `json:"instance_id"`
+		Category   string `json:"category"`
 	} `json:"readers"`
 }
 
@@ -27,19 +32,35 @@ type Instance struct {
 	Instances []struct {
 		InstanceID int `json:"instance_id"`
 		BookID     int `json:"book_id"`
-		Status     int `json:"status"`
+		// Status is decoded per record, so that an unknown status is
+		// reported against its instance instead of failing the whole dump.
+		Status          json.RawMessage `json:"status"`
+		HomeBranchID    int             `json:"home_branch_id"`
+		CurrentBranchID int             `json:"current_branch_id"`
 	} `json:"instances"`
 }
 
 type Book struct {
 	Books []struct {
-		BookID       int    `json:"book_id"`
-		Name         string `json:"name"`
-		AuthorID     int    `json:"author_id"`
-		GenreID      int    `json:"genre_id"`
-		ProductionID int    `json:"production_id"`
-		LanguageID   int    `json:"language_id"`
-		Description  string `json:"description"`
+		BookID       int          `json:"book_id"`
+		Name         string       `json:"name"`
+		AuthorID     int          `json:"author_id"`
+		Authors      []BookAuthor `json:"authors"`
+		GenreID      int          `json:"genre_id"`
+		GenreIDs     []int        `json:"genre_ids"`
+		ProductionID int          `json:"production_id"`
+		LanguageID   int          `json:"language_id"`
+		Description  string       `json:"description"`
+		Category     string       `json:"category"`
+
+		ISBN            string `json:"isbn"`
+		PublicationYear int    `json:"publication_year"`
+		Edition         int    `json:"edition"`
+		PageCount       int    `json:"page_count"`
+		Format          string `json:"format"`
+
+		SeriesID int `json:"series_id"`
+		Volume   int `json:"volume"`
 	} `json:"books"`
 }
 
@@ -81,41 +102,61 @@ type AuthorMapField struct {
 	Surname      string `json:"surname"`
 	Patronymic   string `json:"patronymic"`
 	ProductionID int    `json:"production_id"`
+	Archived     bool   `json:"archived,omitempty"`
 }
 
 type BookMapField struct {
-	Name         string `json:"name"`
-	AuthorID     int    `json:"author_id"`
-	GenreID      int    `json:"genre_id"`
-	ProductionID int    `json:"production_id"`
-	LanguageID   int    `json:"language_id"`
-	Description  string `json:"description"`
+	Name         string       `json:"name"`
+	Authors      []BookAuthor `json:"authors"`
+	GenreIDs     []int        `json:"genre_ids"`
+	ProductionID int          `json:"production_id"`
+	LanguageID   int          `json:"language_id"`
+	Description  string       `json:"description"`
+	Category     string       `json:"category"`
+
+	ISBN            string `json:"isbn,omitempty"`
+	PublicationYear int    `json:"publication_year,omitempty"`
+	Edition         int    `json:"edition,omitempty"`
+	PageCount       int    `json:"page_count,omitempty"`
+	Format          string `json:"format,omitempty"`
+
+	SeriesID int `json:"series_id,omitempty"`
+	Volume   int `json:"volume,omitempty"`
+
+	Archived bool `json:"archived,omitempty"`
 }
 
 type UserMapField struct {
 	Name         string `json:"name"`
-	Password     string `json:"password"`
+	Password     string `json:"-"`
 	LoginStatus  string `json:"login_status"`
 	RegisterDate string `json:"register_date"`
+	Banned       bool   `json:"banned"`
 }
 
 type ReaderMapField struct {
-	InstanceID []int `json:"instance_id"`
+	InstanceID []int  `json:"instance_id"`
+	Category   string `json:"category"`
 }
 
 type InstanceMapField struct {
-	BookID int `json:"book_id"`
-	Status int `json:"status"`
+	BookID          int            `json:"book_id"`
+	Status          InstanceStatus `json:"status"`
+	HomeBranchID    int            `json:"home_branch_id"`
+	CurrentBranchID int            `json:"current_branch_id"`
 }
 
 type ProductionMapField struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived,omitempty"`
 }
 
 type GenreMapField struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived,omitempty"`
 }
 
 type LanguageMapField struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived,omitempty"`
 }
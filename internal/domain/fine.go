@@ -0,0 +1,33 @@
+package book_inventory_system_domain
+
+import "time"
+
+const (
+	FineCharge  = "charge"
+	FinePayment = "payment"
+	FineWaiver  = "waiver"
+)
+
+// FineMapField is a ledger entry. Amounts are in minor currency units.
+type FineMapField struct {
+	FineID   int       `json:"fine_id"`
+	ReaderID int       `json:"reader_id"`
+	Kind     string    `json:"kind"`
+	Amount   int       `json:"amount"`
+	LoanID   int       `json:"loan_id,omitempty"`
+	AdminID  int       `json:"admin_id,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// FineAccount is the state of a reader's fines. Accruing holds the fines of
+// loans that are still overdue and not yet charged to the ledger.
+type FineAccount struct {
+	ReaderID int            `json:"reader_id"`
+	Charged  int            `json:"charged"`
+	Paid     int            `json:"paid"`
+	Waived   int            `json:"waived"`
+	Accruing int            `json:"accruing"`
+	Balance  int            `json:"balance"`
+	Ledger   []FineMapField `json:"ledger"`
+}
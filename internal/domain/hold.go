@@ -0,0 +1,28 @@
+package book_inventory_system_domain
+
+import "time"
+
+const (
+	HoldWaiting   = "waiting"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+	HoldExpired   = "expired"
+)
+
+type HoldMapField struct {
+	HoldID     int        `json:"hold_id"`
+	ReaderID   int        `json:"reader_id"`
+	BookID     int        `json:"book_id"`
+	Status     string     `json:"status"`
+	PlacedDate time.Time  `json:"placed_date"`
+	InstanceID int        `json:"instance_id,omitempty"`
+	ReadyDate  *time.Time `json:"ready_date,omitempty"`
+	ExpireDate *time.Time `json:"expire_date,omitempty"`
+	CloseDate  *time.Time `json:"close_date,omitempty"`
+}
+
+// IsActive reports whether the hold is still queued or waiting for pickup.
+func (h HoldMapField) IsActive() bool {
+	return h.Status == HoldWaiting || h.Status == HoldReady
+}
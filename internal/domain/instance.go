@@ -0,0 +1,144 @@
+package book_inventory_system_domain
+
+import (
+	"fmt"
+	"github.com/goccy/go-json"
+	"strconv"
+)
+
+// InstanceStatus is the lifecycle state of a copy of a book.
+type InstanceStatus string
+
+const (
+	// InstanceAvailable is on the shelf and can be taken.
+	InstanceAvailable InstanceStatus = "available"
+	// InstanceOnLoan is held by a reader.
+	InstanceOnLoan InstanceStatus = "on_loan"
+	// InstanceOnHoldShelf is kept for the reader at the head of the hold queue.
+	InstanceOnHoldShelf InstanceStatus = "on_hold_shelf"
+	// InstanceInTransit is being moved between branches.
+	InstanceInTransit InstanceStatus = "in_transit"
+	// InstanceInRepair is out of circulation until repaired.
+	InstanceInRepair InstanceStatus = "in_repair"
+	// InstanceLost is missing from the library.
+	InstanceLost InstanceStatus = "lost"
+	// InstanceWithdrawn is permanently taken out of circulation.
+	InstanceWithdrawn InstanceStatus = "withdrawn"
+)
+
+// legacyInstanceStatuses maps the numeric statuses of old instances.json
+// dumps to the named ones. Old dumps kept no holds, so a copy reserved
+// there (3) has no hold to wait for and is loaded as available rather than
+// left on the hold shelf for no reader.
+var legacyInstanceStatuses = map[int]InstanceStatus{
+	0: InstanceOnLoan,
+	1: InstanceAvailable,
+	2: InstanceWithdrawn,
+	3: InstanceAvailable,
+}
+
+// instanceTransitions lists the statuses each status can move to. A copy
+// leaves the loan only through a return, which shelves it, or by being
+// declared lost, and a transfer only by arriving or being declared lost. A
+// copy back in circulation is shelved like a returned one, so it goes to the
+// hold shelf when the book is held.
+var instanceTransitions = map[InstanceStatus][]InstanceStatus{
+	InstanceAvailable: {
+		InstanceOnLoan,
+		InstanceInTransit,
+		InstanceInRepair,
+		InstanceLost,
+		InstanceWithdrawn,
+	},
+	InstanceOnLoan: {
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+		InstanceLost,
+	},
+	InstanceOnHoldShelf: {
+		InstanceOnLoan,
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+		InstanceLost,
+	},
+	InstanceInTransit: {
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+		InstanceLost,
+	},
+	InstanceInRepair: {
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+		InstanceLost,
+		InstanceWithdrawn,
+	},
+	InstanceLost: {
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+		InstanceWithdrawn,
+	},
+	InstanceWithdrawn: {
+		InstanceAvailable,
+		InstanceOnHoldShelf,
+	},
+}
+
+// ParseInstanceStatus accepts a status name or a legacy numeric status.
+func ParseInstanceStatus(value string) (InstanceStatus, error) {
+	if number, err := strconv.Atoi(value); err == nil {
+		status, ok := legacyInstanceStatuses[number]
+		if !ok {
+			return "", fmt.Errorf("invalid status %d", number)
+		}
+
+		return status, nil
+	}
+
+	status := InstanceStatus(value)
+	if !status.IsValid() {
+		return "", fmt.Errorf("invalid status %q", value)
+	}
+
+	return status, nil
+}
+
+func (s InstanceStatus) IsValid() bool {
+	_, ok := instanceTransitions[s]
+	return ok
+}
+
+func (s InstanceStatus) CanTransitionTo(to InstanceStatus) bool {
+	for _, status := range instanceTransitions[s] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *InstanceStatus) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		status, ok := legacyInstanceStatuses[number]
+		if !ok {
+			return fmt.Errorf("invalid status %d", number)
+		}
+
+		*s = status
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	status, err := ParseInstanceStatus(name)
+	if err != nil {
+		return err
+	}
+
+	*s = status
+	return nil
+}
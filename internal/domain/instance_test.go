@@ -0,0 +1,70 @@
+package book_inventory_system_domain
+
+import (
+	"testing"
+)
+
+func TestInstanceTransitions(t *testing.T) {
+	tests := []struct {
+		from InstanceStatus
+		to   InstanceStatus
+		want bool
+	}{
+		{from: InstanceAvailable, to: InstanceOnLoan, want: true},
+		{from: InstanceAvailable, to: InstanceInTransit, want: true},
+		{from: InstanceAvailable, to: InstanceOnHoldShelf},
+		{from: InstanceOnLoan, to: InstanceAvailable, want: true},
+		{from: InstanceOnLoan, to: InstanceOnHoldShelf, want: true},
+		{from: InstanceOnLoan, to: InstanceInTransit},
+		{from: InstanceOnLoan, to: InstanceInRepair},
+		{from: InstanceOnHoldShelf, to: InstanceOnLoan, want: true},
+		{from: InstanceOnHoldShelf, to: InstanceOnHoldShelf, want: true},
+		{from: InstanceOnHoldShelf, to: InstanceInTransit},
+		{from: InstanceInTransit, to: InstanceAvailable, want: true},
+		{from: InstanceInTransit, to: InstanceOnHoldShelf, want: true},
+		{from: InstanceInTransit, to: InstanceOnLoan},
+		{from: InstanceInTransit, to: InstanceInRepair},
+		{from: InstanceInRepair, to: InstanceOnHoldShelf, want: true},
+		{from: InstanceInRepair, to: InstanceOnLoan},
+		{from: InstanceLost, to: InstanceAvailable, want: true},
+		{from: InstanceLost, to: InstanceInRepair},
+		{from: InstanceWithdrawn, to: InstanceAvailable, want: true},
+		{from: InstanceWithdrawn, to: InstanceLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInstanceStatus(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    InstanceStatus
+		wantErr bool
+	}{
+		{value: "available", want: InstanceAvailable},
+		{value: "in_transit", want: InstanceInTransit},
+		{value: "0", want: InstanceOnLoan},
+		{value: "3", want: InstanceAvailable},
+		{value: "7", wantErr: true},
+		{value: "borrowed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseInstanceStatus(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInstanceStatus error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseInstanceStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
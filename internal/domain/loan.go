@@ -0,0 +1,82 @@
+package book_inventory_system_domain
+
+import "time"
+
+type LoanMapField struct {
+	LoanID       int        `json:"loan_id"`
+	ReaderID     int        `json:"reader_id"`
+	InstanceID   int        `json:"instance_id"`
+	ItemCategory string     `json:"item_category"`
+	CheckoutDate time.Time  `json:"checkout_date"`
+	DueDate      time.Time  `json:"due_date"`
+	ReturnDate   *time.Time `json:"return_date,omitempty"`
+	FineRate     int        `json:"fine_rate"`
+	FineCap      int        `json:"fine_cap"`
+
+	LoanPeriod  time.Duration `json:"-"`
+	MaxRenewals int           `json:"max_renewals"`
+	Renewals    []LoanRenewal `json:"renewals,omitempty"`
+}
+
+// LoanRenewal records one renewal. A loan renewed within the grace period
+// after it fell due is charged the fine it accrued until then, as Fine counts
+// only the days past the new due date.
+type LoanRenewal struct {
+	RenewDate       time.Time `json:"renew_date"`
+	PreviousDueDate time.Time `json:"previous_due_date"`
+	DueDate         time.Time `json:"due_date"`
+	Fine            int       `json:"fine,omitempty"`
+}
+
+func (l LoanMapField) IsOpen() bool {
+	return l.ReturnDate == nil
+}
+
+// Fine returns the overdue fine of the loan at the given time: the daily rate
+// for every started day past the due date, limited by what the fines charged
+// at renewals left of the cap.
+func (l LoanMapField) Fine(at time.Time) int {
+	if l.ReturnDate != nil {
+		at = *l.ReturnDate
+	}
+
+	if !at.After(l.DueDate) {
+		return 0
+	}
+
+	overdue := at.Sub(l.DueDate)
+	days := int(overdue / (24 * time.Hour))
+	if overdue%(24*time.Hour) != 0 {
+		days++
+	}
+
+	fine := days * l.FineRate
+	if l.FineCap > 0 {
+		left := l.FineCap
+		for _, renewal := range l.Renewals {
+			left -= renewal.Fine
+		}
+
+		fine = min(fine, max(left, 0))
+	}
+
+	return fine
+}
+
+// BookAvailability counts the copies of a book, at one branch if BranchID is
+// set and in the whole library otherwise.
+type BookAvailability struct {
+	BookID    int `json:"book_id"`
+	BranchID  int `json:"branch_id,omitempty"`
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	OnLoan    int `json:"on_loan"`
+	Other     int `json:"other"`
+}
+
+// BookCheckout tells which instance was lent when a book was taken by id.
+type BookCheckout struct {
+	InstanceID int          `json:"instance_id"`
+	DueDate    time.Time    `json:"due_date"`
+	Book       BookMapField `json:"book"`
+}
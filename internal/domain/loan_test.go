@@ -0,0 +1,35 @@
+package book_inventory_system_domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoanFine(t *testing.T) {
+	due := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	loan := LoanMapField{DueDate: due, FineRate: 10, FineCap: 50}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		renewals []LoanRenewal
+		want     int
+	}{
+		{name: "before the due date", at: due.Add(-time.Hour)},
+		{name: "at the due date", at: due},
+		{name: "first started day", at: due.Add(time.Minute), want: 10},
+		{name: "whole days", at: due.Add(48 * time.Hour), want: 20},
+		{name: "capped", at: due.Add(30 * 24 * time.Hour), want: 50},
+		{name: "cap left by renewals", at: due.Add(30 * 24 * time.Hour), renewals: []LoanRenewal{{Fine: 20}}, want: 30},
+		{name: "cap used up by renewals", at: due.Add(30 * 24 * time.Hour), renewals: []LoanRenewal{{Fine: 30}, {Fine: 30}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan.Renewals = tt.renewals
+			if got := loan.Fine(tt.at); got != tt.want {
+				t.Errorf("Fine = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package book_inventory_system_domain
+
+// PageRequest selects a page of a list. Items are ordered by Sort, with the id
+// as a tie breaker. Cursor, when set, continues from the page that returned
+// it and replaces Offset.
+type PageRequest struct {
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// Page is a slice of a sorted list. Total counts the whole list, NextCursor is
+// empty on the last page.
+type Page[T any] struct {
+	Total      int    `json:"total"`
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type AuthorEntry struct {
+	AuthorID int `json:"author_id"`
+	AuthorMapField
+}
+
+type GenreEntry struct {
+	GenreID int `json:"genre_id"`
+	GenreMapField
+}
+
+type LanguageEntry struct {
+	LanguageID int `json:"language_id"`
+	LanguageMapField
+}
+
+type ProductionEntry struct {
+	ProductionID int `json:"production_id"`
+	ProductionMapField
+}
+
+type InstanceEntry struct {
+	InstanceID int `json:"instance_id"`
+	InstanceMapField
+}
+
+type UserEntry struct {
+	UserID int `json:"user_id"`
+	UserMapField
+}
+
+type ReaderEntry struct {
+	ReaderID int `json:"reader_id"`
+	ReaderMapField
+}
@@ -0,0 +1,37 @@
+package book_inventory_system_domain
+
+import "time"
+
+// LoanTerms are the lending rule values a loan is opened with.
+type LoanTerms struct {
+	ReaderCategory string        `json:"reader_category"`
+	ItemCategory   string        `json:"item_category"`
+	MaxLoans       int           `json:"max_loans"`
+	LoanPeriod     time.Duration `json:"-"`
+	MaxRenewals    int           `json:"max_renewals"`
+	FineRate       int           `json:"fine_rate"`
+	FineCap        int           `json:"fine_cap"`
+}
+
+// CheckoutContext is what the lending policy needs to know about a checkout.
+// Problems lists the reasons the checkout is impossible regardless of policy.
+type CheckoutContext struct {
+	ReaderID       int
+	InstanceID     int
+	BookID         int
+	ReaderCategory string
+	ItemCategory   string
+	OpenLoans      int
+	Problems       []string
+}
+
+type CheckoutDecision struct {
+	Allowed        bool       `json:"allowed"`
+	ReaderID       int        `json:"reader_id"`
+	InstanceID     int        `json:"instance_id"`
+	ReaderCategory string     `json:"reader_category"`
+	ItemCategory   string     `json:"item_category"`
+	OpenLoans      int        `json:"open_loans"`
+	Terms          *LoanTerms `json:"terms,omitempty"`
+	Reasons        []string   `json:"reasons,omitempty"`
+}
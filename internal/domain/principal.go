@@ -0,0 +1,46 @@
+package book_inventory_system_domain
+
+import (
+	"time"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleReader = "reader"
+)
+
+const (
+	AuthSession = "session"
+	AuthBearer  = "bearer"
+)
+
+// Principal is the authenticated caller of a request. A user is a reader
+// when there is a reader record with the same id and an admin when listed in
+// the admin dump. Method tells how the caller authenticated.
+type Principal struct {
+	UserID int      `json:"user_id"`
+	Roles  []string `json:"roles"`
+	Method string   `json:"method,omitempty"`
+}
+
+// HasRole reports whether the principal has any of the roles.
+func (p Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		for _, own := range p.Roles {
+			if own == role {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// BearerToken is a signed token identifying a user until ExpireDate. Unlike
+// a session it is not kept on the server, so it survives a logout and only
+// a ban ends it early.
+type BearerToken struct {
+	Token      string    `json:"token"`
+	UserID     int       `json:"user_id"`
+	ExpireDate time.Time `json:"expire_date"`
+}
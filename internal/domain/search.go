@@ -0,0 +1,9 @@
+package book_inventory_system_domain
+
+// SearchResult is a book found by a catalog search with its relevance score
+// and availability in the whole library.
+type SearchResult struct {
+	BookEntry
+	Score        float64          `json:"score"`
+	Availability BookAvailability `json:"availability"`
+}
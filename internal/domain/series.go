@@ -0,0 +1,40 @@
+package book_inventory_system_domain
+
+type Series struct {
+	Series []struct {
+		SeriesID    int    `json:"series_id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"series"`
+}
+
+type SeriesMapField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SeriesListing is a series with its volumes in reading order.
+type SeriesListing struct {
+	SeriesID    int            `json:"series_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Volumes     []SeriesVolume `json:"volumes"`
+}
+
+type SeriesVolume struct {
+	Volume       int              `json:"volume"`
+	BookID       int              `json:"book_id"`
+	Name         string           `json:"name"`
+	Availability BookAvailability `json:"availability"`
+}
+
+// SeriesSuggestion is the next unread volume of a series the reader has
+// borrowed from.
+type SeriesSuggestion struct {
+	SeriesID       int    `json:"series_id"`
+	SeriesName     string `json:"series_name"`
+	LastReadVolume int    `json:"last_read_volume"`
+	Volume         int    `json:"volume"`
+	BookID         int    `json:"book_id"`
+	Name           string `json:"name"`
+}
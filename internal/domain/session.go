@@ -0,0 +1,32 @@
+package book_inventory_system_domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidCredentials = errors.New("invalid name or password")
+
+// SessionMapField is a login of a user. It ends at ExpireDate, or earlier
+// when the user has been idle until IdleExpireDate.
+type SessionMapField struct {
+	SessionID      int       `json:"session_id"`
+	UserID         int       `json:"user_id"`
+	CreateDate     time.Time `json:"create_date"`
+	LastActiveDate time.Time `json:"last_active_date"`
+	IdleExpireDate time.Time `json:"idle_expire_date"`
+	ExpireDate     time.Time `json:"expire_date"`
+}
+
+// IsActive reports whether the session has neither expired nor been idle
+// for too long.
+func (s SessionMapField) IsActive(now time.Time) bool {
+	return now.Before(s.ExpireDate) && now.Before(s.IdleExpireDate)
+}
+
+// Login is a new session with the token identifying it. The token is only
+// ever returned here.
+type Login struct {
+	Token   string          `json:"token"`
+	Session SessionMapField `json:"session"`
+}
@@ -0,0 +1,33 @@
+package book_inventory_system_domain
+
+import "time"
+
+const (
+	StocktakeOpen   = "open"
+	StocktakeClosed = "closed"
+)
+
+type StocktakeMapField struct {
+	StocktakeID int              `json:"stocktake_id"`
+	AdminID     int              `json:"admin_id"`
+	BranchID    int              `json:"branch_id"`
+	Status      string           `json:"status"`
+	OpenDate    time.Time        `json:"open_date"`
+	CloseDate   *time.Time       `json:"close_date,omitempty"`
+	Scanned     []int            `json:"scanned"`
+	Report      *StocktakeReport `json:"report,omitempty"`
+}
+
+// StocktakeReport reconciles the scanned copies with the ones recorded at the
+// branch. Missing copies should have been on the shelf or the hold shelf but
+// were not scanned, OnLoan copies were scanned although they are recorded as
+// on loan and Misplaced copies were scanned although they are recorded at
+// another branch.
+type StocktakeReport struct {
+	Found      int   `json:"found"`
+	Missing    []int `json:"missing"`
+	OnLoan     []int `json:"on_loan"`
+	Misplaced  []int `json:"misplaced"`
+	Unknown    []int `json:"unknown"`
+	MarkedLost []int `json:"marked_lost,omitempty"`
+}
@@ -0,0 +1,16 @@
+package book_inventory_system_domain
+
+import "fmt"
+
+// Violation is a data integrity problem found in a dump file. Record is the
+// 1-based position of the record in the file and ID the id it carries.
+type Violation struct {
+	File    string `json:"file"`
+	Record  int    `json:"record"`
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: record %d (id %d): %s", v.File, v.Record, v.ID, v.Message)
+}
@@ -0,0 +1,435 @@
+package book_inventory_system_handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) getFunds(ctx *gin.Context) {
+	funds := h.s.GetFunds()
+
+	response, err := json.Marshal(funds)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) createOrder(ctx *gin.Context) {
+	productionID := ctx.Query("production_id")
+	fund := ctx.Query("fund")
+
+	intProductionID, err := strconv.Atoi(productionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.CreateOrder(h.actor(ctx), intProductionID, fund)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) addOrderLine(ctx *gin.Context) {
+	orderID := ctx.Query("order_id")
+	bookID := ctx.Query("book_id")
+	quantity := ctx.Query("quantity")
+	unitPrice := ctx.Query("unit_price")
+
+	intOrderID, err := strconv.Atoi(orderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intQuantity, err := strconv.Atoi(quantity)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intUnitPrice, err := strconv.Atoi(unitPrice)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.AddOrderLine(h.actor(ctx), intOrderID, intBookID, intQuantity, intUnitPrice)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) sendOrder(ctx *gin.Context) {
+	orderID := ctx.Query("order_id")
+
+	intOrderID, err := strconv.Atoi(orderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.SendOrder(h.actor(ctx), intOrderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) receiveOrder(ctx *gin.Context) {
+	orderID := ctx.Query("order_id")
+	bookID := ctx.Query("book_id")
+	quantity := ctx.Query("quantity")
+
+	intOrderID, err := strconv.Atoi(orderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intQuantity, err := strconv.Atoi(quantity)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.ReceiveOrder(h.actor(ctx), intOrderID, intBookID, intQuantity)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) cancelOrder(ctx *gin.Context) {
+	orderID := ctx.Query("order_id")
+
+	intOrderID, err := strconv.Atoi(orderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.CancelOrder(h.actor(ctx), intOrderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getOrder(ctx *gin.Context) {
+	orderID := ctx.Query("order_id")
+
+	intOrderID, err := strconv.Atoi(orderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	order, err := h.s.GetOrder(intOrderID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getProductionOrders(ctx *gin.Context) {
+	productionID := ctx.Query("production_id")
+
+	intProductionID, err := strconv.Atoi(productionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	orders, err := h.s.GetProductionOrders(intProductionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(orders)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
@@ -0,0 +1,140 @@
+package book_inventory_system_handler
+
+import (
+	audit "book-inventory-system/pkg/audit"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+
+	maxRequestIDLength = 64
+)
+
+// requestID tags every request with an id, the one sent in the X-Request-ID
+// header or a random one, and echoes it back in the response.
+func requestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			b := make([]byte, 16)
+			_, err := rand.Read(b)
+			if err == nil {
+				id = hex.EncodeToString(b)
+			}
+		}
+
+		ctx.Set(requestIDKey, id)
+		ctx.Header(requestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+func (h *Handler) getAuditLog(ctx *gin.Context) {
+	actorID := ctx.DefaultQuery("actor_id", "0")
+	entityID := ctx.DefaultQuery("entity_id", "0")
+	entity := ctx.Query("entity")
+	from := ctx.Query("from")
+	to := ctx.Query("to")
+
+	intActorID, err := strconv.Atoi(actorID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intEntityID, err := strconv.Atoi(entityID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	// from and to are RFC 3339 times, either may be left out
+	var timeFrom, timeTo time.Time
+	if from != "" {
+		timeFrom, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			_, err = ctx.Writer.Write([]byte("internal server error"))
+			if err != nil {
+				h.l.Errorf("response error: %v", err)
+				return
+			}
+
+			return
+		}
+	}
+
+	if to != "" {
+		timeTo, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			_, err = ctx.Writer.Write([]byte("internal server error"))
+			if err != nil {
+				h.l.Errorf("response error: %v", err)
+				return
+			}
+
+			return
+		}
+	}
+
+	principal, _ := h.principal(ctx)
+	entries, err := h.s.GetAuditLog(principal, audit.Filter{
+		Actor:    intActorID,
+		Entity:   entity,
+		EntityID: intEntityID,
+		From:     timeFrom,
+		To:       timeTo,
+	})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(entries)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
@@ -0,0 +1,231 @@
+package book_inventory_system_handler
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const principalKey = "principal"
+
+// authenticate identifies the caller by a signed bearer token or else by a
+// session token, and stores the principal in the context. Requests without
+// credentials pass on anonymously, and so do ones whose session has ended:
+// the session cookie outlives its session, so it is cleared rather than
+// refused, which would lock a browser out of even the login. Requests with
+// an invalid bearer token are refused.
+func (h *Handler) authenticate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearer, ok := bearerToken(ctx)
+		if ok && isSignedToken(bearer) {
+			principal, err := h.s.AuthenticateToken(bearer)
+			if err != nil {
+				ctx.Status(http.StatusUnauthorized)
+				_, err = ctx.Writer.Write([]byte(fmt.Sprintf("unauthorized: %v", err)))
+				if err != nil {
+					h.l.Errorf("response error: %v", err)
+				}
+
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set(principalKey, *principal)
+			ctx.Next()
+			return
+		}
+
+		session := sessionToken(ctx)
+		if session == "" {
+			ctx.Next()
+			return
+		}
+
+		principal, err := h.s.AuthenticateSession(session)
+		if err != nil {
+			clearSessionCookie(ctx)
+			ctx.Next()
+			return
+		}
+
+		ctx.Set(principalKey, *principal)
+		ctx.Next()
+	}
+}
+
+// bearerToken returns the token of the Authorization header, if it is a
+// bearer token.
+func bearerToken(ctx *gin.Context) (string, bool) {
+	token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+// isSignedToken tells signed tokens from session tokens by the dot between
+// their payload and signature, which base64url session tokens never have.
+func isSignedToken(token string) bool {
+	return strings.Contains(token, ".")
+}
+
+// require refuses requests of anonymous callers with 401 and of callers
+// with none of the roles with 403. Without roles any authenticated caller
+// passes.
+func (h *Handler) require(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := h.principal(ctx)
+		if !ok {
+			ctx.Status(http.StatusUnauthorized)
+			_, err := ctx.Writer.Write([]byte("unauthorized"))
+			if err != nil {
+				h.l.Errorf("response error: %v", err)
+			}
+
+			ctx.Abort()
+			return
+		}
+
+		if len(roles) > 0 && !principal.HasRole(roles...) {
+			ctx.Status(http.StatusForbidden)
+			_, err := ctx.Writer.Write([]byte("permission denied"))
+			if err != nil {
+				h.l.Errorf("response error: %v", err)
+			}
+
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// principal returns the authenticated caller of the request, if any.
+func (h *Handler) principal(ctx *gin.Context) (domain.Principal, bool) {
+	value, ok := ctx.Get(principalKey)
+	if !ok {
+		return domain.Principal{}, false
+	}
+
+	principal, ok := value.(domain.Principal)
+
+	return principal, ok
+}
+
+// actor identifies the caller of a privileged operation for the audit log.
+func (h *Handler) actor(ctx *gin.Context) domain.Actor {
+	principal, _ := h.principal(ctx)
+
+	return domain.Actor{
+		Principal: principal,
+		RequestID: ctx.GetString(requestIDKey),
+	}
+}
+
+// readerID returns the reader a request acts for. That is the caller, but
+// an admin at the desk may act for any reader given as reader_id. When the
+// caller may not act for the reader, readerID answers the request itself
+// and returns false.
+func (h *Handler) readerID(ctx *gin.Context) (int, bool) {
+	principal, _ := h.principal(ctx)
+
+	readerID := ctx.Query("reader_id")
+	if readerID == "" {
+		if principal.HasRole(domain.RoleReader) {
+			return principal.UserID, true
+		}
+
+		ctx.Status(http.StatusForbidden)
+		_, err := ctx.Writer.Write([]byte("caller is not a reader"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+		}
+
+		return 0, false
+	}
+
+	intReaderID, err := strconv.Atoi(readerID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+		}
+
+		return 0, false
+	}
+
+	if intReaderID != principal.UserID && !principal.HasRole(domain.RoleAdmin) {
+		ctx.Status(http.StatusForbidden)
+		_, err = ctx.Writer.Write([]byte("permission denied"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+		}
+
+		return 0, false
+	}
+
+	return intReaderID, true
+}
+
+func (h *Handler) getPrincipal(ctx *gin.Context) {
+	principal, _ := h.principal(ctx)
+
+	response, err := json.Marshal(principal)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) issueToken(ctx *gin.Context) {
+	principal, _ := h.principal(ctx)
+
+	token, err := h.s.IssueToken(principal)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(token)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
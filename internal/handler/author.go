@@ -0,0 +1,225 @@
+package book_inventory_system_handler
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) getAuthor(ctx *gin.Context) {
+	authorID := ctx.Query("author_id")
+
+	intAuthorID, err := strconv.Atoi(authorID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	author, err := h.s.GetAuthor(intAuthorID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(author)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) createAuthor(ctx *gin.Context) {
+	productionID := ctx.DefaultQuery("production_id", "0")
+	name := ctx.Query("name")
+	surname := ctx.Query("surname")
+	patronymic := ctx.Query("patronymic")
+
+	intProductionID, err := strconv.Atoi(productionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	author, err := h.s.CreateAuthor(h.actor(ctx), domain.AuthorMapField{
+		Name:         name,
+		Surname:      surname,
+		Patronymic:   patronymic,
+		ProductionID: intProductionID,
+	})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(author)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) updateAuthor(ctx *gin.Context) {
+	authorID := ctx.Query("author_id")
+	productionID := ctx.DefaultQuery("production_id", "0")
+	name := ctx.Query("name")
+	surname := ctx.Query("surname")
+	patronymic := ctx.Query("patronymic")
+
+	intAuthorID, err := strconv.Atoi(authorID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intProductionID, err := strconv.Atoi(productionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	author, err := h.s.UpdateAuthor(h.actor(ctx), intAuthorID, domain.AuthorMapField{
+		Name:         name,
+		Surname:      surname,
+		Patronymic:   patronymic,
+		ProductionID: intProductionID,
+	})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(author)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) deleteAuthor(ctx *gin.Context) {
+	authorID := ctx.Query("author_id")
+
+	intAuthorID, err := strconv.Atoi(authorID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	result, err := h.s.DeleteAuthor(h.actor(ctx), intAuthorID)
+	if err != nil {
+		h.writeDeleteError(ctx, err)
+		return
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
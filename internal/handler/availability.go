@@ -0,0 +1,171 @@
+package book_inventory_system_handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) checkBookAvailability(ctx *gin.Context) {
+	bookID := ctx.Query("book_id")
+	branchID := ctx.DefaultQuery("branch_id", "0")
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intBranchID, err := strconv.Atoi(branchID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	availability, err := h.s.CheckBookAvailability(intBookID, intBranchID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(availability)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getBooksAvailability(ctx *gin.Context) {
+	branchID := ctx.DefaultQuery("branch_id", "0")
+
+	intBranchID, err := strconv.Atoi(branchID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	availability, err := h.s.GetBooksAvailability(intBranchID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(availability)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) takeBookCopy(ctx *gin.Context) {
+	bookID := ctx.Query("book_id")
+
+	intReaderID, ok := h.readerID(ctx)
+	if !ok {
+		return
+	}
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	checkout, err := h.s.TakeBookCopy(intReaderID, intBookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(checkout)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
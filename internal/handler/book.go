@@ -0,0 +1,294 @@
+package book_inventory_system_handler
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) getBookByISBN(ctx *gin.Context) {
+	isbn := ctx.Query("isbn")
+
+	book, err := h.s.GetBookByISBN(isbn)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(book)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getBook(ctx *gin.Context) {
+	bookID := ctx.Query("book_id")
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	book, err := h.s.GetBook(intBookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(book)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) createBook(ctx *gin.Context) {
+	book, err := parseBook(ctx)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	created, err := h.s.CreateBook(h.actor(ctx), book)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(created)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) updateBook(ctx *gin.Context) {
+	bookID := ctx.Query("book_id")
+
+	book, err := parseBook(ctx)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	updated, err := h.s.UpdateBook(h.actor(ctx), intBookID, book)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(updated)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) deleteBook(ctx *gin.Context) {
+	bookID := ctx.Query("book_id")
+
+	intBookID, err := strconv.Atoi(bookID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	result, err := h.s.DeleteBook(h.actor(ctx), intBookID)
+	if err != nil {
+		h.writeDeleteError(ctx, err)
+		return
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+// parseBook reads a book from the query. authors is a comma separated list of
+// author_id:role pairs, the role defaulting to author, and genre_ids a comma
+// separated list of ids.
+func parseBook(ctx *gin.Context) (domain.BookMapField, error) {
+	book := domain.BookMapField{
+		Name:        ctx.Query("name"),
+		Description: ctx.Query("description"),
+		Category:    ctx.Query("category"),
+		ISBN:        ctx.Query("isbn"),
+		Format:      ctx.Query("format"),
+	}
+
+	var err error
+	for _, author := range strings.Split(ctx.Query("authors"), ",") {
+		if strings.TrimSpace(author) == "" {
+			continue
+		}
+
+		authorID, role, _ := strings.Cut(author, ":")
+		bookAuthor := domain.BookAuthor{Role: strings.TrimSpace(role)}
+		bookAuthor.AuthorID, err = strconv.Atoi(strings.TrimSpace(authorID))
+		if err != nil {
+			return book, err
+		}
+
+		book.Authors = append(book.Authors, bookAuthor)
+	}
+
+	book.GenreIDs, err = parseIDs(ctx.Query("genre_ids"))
+	if err != nil {
+		return book, err
+	}
+
+	for query, value := range map[string]*int{
+		"production_id":    &book.ProductionID,
+		"language_id":      &book.LanguageID,
+		"publication_year": &book.PublicationYear,
+		"edition":          &book.Edition,
+		"page_count":       &book.PageCount,
+		"series_id":        &book.SeriesID,
+		"volume":           &book.Volume,
+	} {
+		*value, err = strconv.Atoi(ctx.DefaultQuery(query, "0"))
+		if err != nil {
+			return book, err
+		}
+	}
+
+	return book, nil
+}
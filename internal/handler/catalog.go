@@ -0,0 +1,112 @@
+package book_inventory_system_handler
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// getCatalog lists books filtered by comma separated genre_id, language_id,
+// production_id and author_id lists and by available=true|false.
+func (h *Handler) getCatalog(ctx *gin.Context) {
+	genreIDs := ctx.Query("genre_id")
+	languageIDs := ctx.Query("language_id")
+	productionIDs := ctx.Query("production_id")
+	authorIDs := ctx.Query("author_id")
+	available := ctx.Query("available")
+	branchID := ctx.DefaultQuery("branch_id", "0")
+
+	var (
+		filter domain.CatalogFilter
+		err    error
+	)
+
+	filter.GenreIDs, err = parseIDs(genreIDs)
+	if err == nil {
+		filter.LanguageIDs, err = parseIDs(languageIDs)
+	}
+
+	if err == nil {
+		filter.ProductionIDs, err = parseIDs(productionIDs)
+	}
+
+	if err == nil {
+		filter.AuthorIDs, err = parseIDs(authorIDs)
+	}
+
+	if err == nil {
+		filter.BranchID, err = strconv.Atoi(branchID)
+	}
+
+	if err == nil && available != "" {
+		var boolAvailable bool
+		boolAvailable, err = strconv.ParseBool(available)
+		filter.Available = &boolAvailable
+	}
+
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	listing, err := h.s.GetCatalog(filter)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(listing)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+// parseIDs parses a comma separated list of ids. An empty value gives no ids.
+func parseIDs(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	ids := make([]int, 0)
+	for _, id := range strings.Split(value, ",") {
+		intID, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, intID)
+	}
+
+	return ids, nil
+}
@@ -2,23 +2,101 @@ package book_inventory_system_handler
 
 import (
 	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
 	logger "book-inventory-system/pkg/logger"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/goccy/go-json"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type service interface {
-	ReturnBook(id int) error
-	TakeBook(id int) (*domain.BookMapField, error)
-	UpdateLoginStatus(id int, status string) error
-	BanUser(userID, adminID int) error
-	UpdateInstanceStatus(instanceID, status int) error
+	ReturnBook(readerID, instanceID int) error
+	TakeBook(readerID, instanceID int) (*domain.BookMapField, error)
+	Login(name, password string) (*domain.Login, error)
+	Logout(token string) error
+	Authenticate(token string) (*domain.SessionMapField, error)
+	AuthenticateSession(token string) (*domain.Principal, error)
+	AuthenticateToken(token string) (*domain.Principal, error)
+	IssueToken(principal domain.Principal) (*domain.BearerToken, error)
+	GetUserSessions(principal domain.Principal, userID int) ([]domain.SessionMapField, error)
+	RevokeSession(actor domain.Actor, sessionID int) (*domain.SessionMapField, error)
+	BanUser(actor domain.Actor, userID int, reason string, duration time.Duration) (*domain.BanMapField, error)
+	UnbanUser(actor domain.Actor, userID int, reason string) (*domain.BanMapField, error)
+	GetUserBans(userID int) ([]domain.BanMapField, error)
+	UpdateInstanceStatus(actor domain.Actor, instanceID int, status domain.InstanceStatus) error
+	GetAuditLog(principal domain.Principal, filter audit.Filter) ([]audit.Entry, error)
 	CheckAvailability(instanceID int) (bool, error)
 	CountPublishedBooks(authorID int) (int, error)
 	CheckBorrowBooks(readerID int) ([]domain.BookMapField, error)
+	GetBookByISBN(isbn string) (*domain.BookEntry, error)
+	GetBook(bookID int) (*domain.BookEntry, error)
+	CreateBook(actor domain.Actor, book domain.BookMapField) (*domain.BookEntry, error)
+	UpdateBook(actor domain.Actor, bookID int, book domain.BookMapField) (*domain.BookEntry, error)
+	DeleteBook(actor domain.Actor, bookID int) (*domain.DeleteResult, error)
+	GetAuthor(authorID int) (*domain.AuthorEntry, error)
+	CreateAuthor(actor domain.Actor, author domain.AuthorMapField) (*domain.AuthorEntry, error)
+	UpdateAuthor(actor domain.Actor, authorID int, author domain.AuthorMapField) (*domain.AuthorEntry, error)
+	DeleteAuthor(actor domain.Actor, authorID int) (*domain.DeleteResult, error)
+	GetGenre(genreID int) (*domain.GenreEntry, error)
+	CreateGenre(actor domain.Actor, genre domain.GenreMapField) (*domain.GenreEntry, error)
+	UpdateGenre(actor domain.Actor, genreID int, genre domain.GenreMapField) (*domain.GenreEntry, error)
+	DeleteGenre(actor domain.Actor, genreID int) (*domain.DeleteResult, error)
+	GetLanguage(languageID int) (*domain.LanguageEntry, error)
+	CreateLanguage(actor domain.Actor, language domain.LanguageMapField) (*domain.LanguageEntry, error)
+	UpdateLanguage(actor domain.Actor, languageID int, language domain.LanguageMapField) (*domain.LanguageEntry, error)
+	DeleteLanguage(actor domain.Actor, languageID int) (*domain.DeleteResult, error)
+	GetProduction(productionID int) (*domain.ProductionEntry, error)
+	CreateProduction(actor domain.Actor, production domain.ProductionMapField) (*domain.ProductionEntry, error)
+	UpdateProduction(actor domain.Actor, productionID int, production domain.ProductionMapField) (*domain.ProductionEntry, error)
+	DeleteProduction(actor domain.Actor, productionID int) (*domain.DeleteResult, error)
+	GetSeries(seriesID, branchID int) (*domain.SeriesListing, error)
+	NextInSeries(readerID int) ([]domain.SeriesSuggestion, error)
+	Search(query string, limit int) ([]domain.SearchResult, error)
+	GetCatalog(filter domain.CatalogFilter) (*domain.CatalogListing, error)
+	ListBooks(req domain.PageRequest) (*domain.Page[domain.BookEntry], error)
+	ListAuthors(req domain.PageRequest) (*domain.Page[domain.AuthorEntry], error)
+	ListGenres(req domain.PageRequest) (*domain.Page[domain.GenreEntry], error)
+	ListLanguages(req domain.PageRequest) (*domain.Page[domain.LanguageEntry], error)
+	ListProductions(req domain.PageRequest) (*domain.Page[domain.ProductionEntry], error)
+	ListInstances(req domain.PageRequest) (*domain.Page[domain.InstanceEntry], error)
+	ListUsers(req domain.PageRequest) (*domain.Page[domain.UserEntry], error)
+	ListReaders(req domain.PageRequest) (*domain.Page[domain.ReaderEntry], error)
+	GetInstanceLoan(instanceID int) (*domain.LoanMapField, error)
+	GetReaderLoans(readerID int) ([]domain.LoanMapField, error)
+	RenewLoan(readerID, loanID int) (*domain.LoanMapField, error)
+	ExplainCheckout(readerID, instanceID int) (*domain.CheckoutDecision, error)
+	CheckBookAvailability(bookID, branchID int) (*domain.BookAvailability, error)
+	GetBooksAvailability(branchID int) ([]domain.BookAvailability, error)
+	TakeBookCopy(readerID, bookID int) (*domain.BookCheckout, error)
+	OpenStocktake(actor domain.Actor, branchID int) (*domain.StocktakeMapField, error)
+	ScanStocktake(stocktakeID int, instanceIDs []int) (*domain.StocktakeMapField, error)
+	CloseStocktake(actor domain.Actor, stocktakeID int, markLost bool) (*domain.StocktakeMapField, error)
+	GetStocktake(stocktakeID int) (*domain.StocktakeMapField, error)
+	GetBranches() map[int]domain.BranchMapField
+	RequestTransfer(readerID, bookID, branchID int) (*domain.TransferMapField, error)
+	DispatchTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error)
+	ReceiveTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error)
+	CancelTransfer(transferID, readerID int) error
+	GetTransfer(transferID int) (*domain.TransferMapField, error)
+	GetBranchTransfers(branchID int) ([]domain.TransferMapField, error)
+	GetFunds() []domain.FundMapField
+	CreateOrder(actor domain.Actor, productionID int, fundName string) (*domain.OrderMapField, error)
+	AddOrderLine(actor domain.Actor, orderID, bookID, quantity, unitPrice int) (*domain.OrderMapField, error)
+	SendOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error)
+	ReceiveOrder(actor domain.Actor, orderID, bookID, quantity int) (*domain.OrderMapField, error)
+	CancelOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error)
+	GetOrder(orderID int) (*domain.OrderMapField, error)
+	GetProductionOrders(productionID int) ([]domain.OrderMapField, error)
+	PlaceHold(readerID, bookID int) (*domain.HoldMapField, error)
+	CancelHold(readerID, holdID int) error
+	GetReaderHolds(readerID int) ([]domain.HoldMapField, error)
+	GetBookHolds(bookID int) ([]domain.HoldMapField, error)
+	GetFines(readerID int) (*domain.FineAccount, error)
+	PayFine(actor domain.Actor, readerID, amount int) (*domain.FineAccount, error)
+	WaiveFine(actor domain.Actor, readerID, amount int, reason string) (*domain.FineAccount, error)
 }
 
 type Handler struct {
@@ -35,15 +113,98 @@ func New(l logger.Logger, s service) *Handler {
 
 func (h *Handler) InitRoutes(address string, ch chan error) {
 	router := gin.Default()
+	router.Use(requestID(), h.authenticate())
 	router.GET("/", h.main)
-	router.GET("/return_book", h.returnBook)
-	router.GET("/take_book", h.takeBook)
-	router.GET("/update_login_status", h.updateLoginStatus)
-	router.GET("/ban_user", h.banUser)
-	router.GET("/update_instance_status", h.updateInstanceStatus)
+	router.POST("/login", h.login)
 	router.GET("/check_availability", h.checkAvailability)
+	router.GET("/check_book_availability", h.checkBookAvailability)
+	router.GET("/get_books_availability", h.getBooksAvailability)
+	router.GET("/get_branches", h.getBranches)
 	router.GET("/count_published_books", h.countPublishedBooks)
-	router.GET("/check_borrow_books", h.checkBorrowBooks)
+	router.GET("/get_book_by_isbn", h.getBookByISBN)
+	router.GET("/get_book", h.getBook)
+	router.GET("/get_author", h.getAuthor)
+	router.GET("/get_genre", h.getGenre)
+	router.GET("/get_language", h.getLanguage)
+	router.GET("/get_production", h.getProduction)
+	router.GET("/get_series", h.getSeries)
+	router.GET("/search", h.search)
+	router.GET("/get_catalog", h.getCatalog)
+	router.GET("/list_books", h.listBooks)
+	router.GET("/list_authors", h.listAuthors)
+	router.GET("/list_genres", h.listGenres)
+	router.GET("/list_languages", h.listLanguages)
+	router.GET("/list_productions", h.listProductions)
+	router.GET("/list_instances", h.listInstances)
+
+	authenticated := router.Group("/", h.require())
+	authenticated.POST("/logout", h.logout)
+	authenticated.GET("/get_session", h.getSession)
+	authenticated.GET("/get_principal", h.getPrincipal)
+	authenticated.POST("/issue_token", h.issueToken)
+
+	// an admin may act for a reader at the desk, see readerID
+	readers := router.Group("/", h.require(domain.RoleReader, domain.RoleAdmin))
+	readers.GET("/return_book", h.returnBook)
+	readers.GET("/take_book", h.takeBook)
+	readers.GET("/take_book_copy", h.takeBookCopy)
+	readers.GET("/check_borrow_books", h.checkBorrowBooks)
+	readers.GET("/get_reader_loans", h.getReaderLoans)
+	readers.GET("/renew_loan", h.renewLoan)
+	readers.GET("/explain_checkout", h.explainCheckout)
+	readers.GET("/place_hold", h.placeHold)
+	readers.GET("/cancel_hold", h.cancelHold)
+	readers.GET("/get_reader_holds", h.getReaderHolds)
+	readers.GET("/get_fines", h.getFines)
+	readers.GET("/request_transfer", h.requestTransfer)
+	readers.GET("/cancel_transfer", h.cancelTransfer)
+	readers.GET("/next_in_series", h.nextInSeries)
+
+	admins := router.Group("/", h.require(domain.RoleAdmin))
+	admins.GET("/get_user_sessions", h.getUserSessions)
+	admins.GET("/revoke_session", h.revokeSession)
+	admins.GET("/ban_user", h.banUser)
+	admins.GET("/unban_user", h.unbanUser)
+	admins.GET("/get_user_bans", h.getUserBans)
+	admins.GET("/get_audit_log", h.getAuditLog)
+	admins.GET("/update_instance_status", h.updateInstanceStatus)
+	admins.GET("/open_stocktake", h.openStocktake)
+	admins.GET("/scan_stocktake", h.scanStocktake)
+	admins.GET("/close_stocktake", h.closeStocktake)
+	admins.GET("/get_stocktake", h.getStocktake)
+	admins.GET("/dispatch_transfer", h.dispatchTransfer)
+	admins.GET("/receive_transfer", h.receiveTransfer)
+	admins.GET("/get_transfer", h.getTransfer)
+	admins.GET("/get_branch_transfers", h.getBranchTransfers)
+	admins.GET("/get_funds", h.getFunds)
+	admins.GET("/create_order", h.createOrder)
+	admins.GET("/add_order_line", h.addOrderLine)
+	admins.GET("/send_order", h.sendOrder)
+	admins.GET("/receive_order", h.receiveOrder)
+	admins.GET("/cancel_order", h.cancelOrder)
+	admins.GET("/get_order", h.getOrder)
+	admins.GET("/get_production_orders", h.getProductionOrders)
+	admins.GET("/create_book", h.createBook)
+	admins.GET("/update_book", h.updateBook)
+	admins.GET("/delete_book", h.deleteBook)
+	admins.GET("/create_author", h.createAuthor)
+	admins.GET("/update_author", h.updateAuthor)
+	admins.GET("/delete_author", h.deleteAuthor)
+	admins.GET("/create_genre", h.createGenre)
+	admins.GET("/update_genre", h.updateGenre)
+	admins.GET("/delete_genre", h.deleteGenre)
+	admins.GET("/create_language", h.createLanguage)
+	admins.GET("/update_language", h.updateLanguage)
+	admins.GET("/delete_language", h.deleteLanguage)
+	admins.GET("/create_production", h.createProduction)
+	admins.GET("/update_production", h.updateProduction)
+	admins.GET("/delete_production", h.deleteProduction)
+	admins.GET("/list_users", h.listUsers)
+	admins.GET("/list_readers", h.listReaders)
+	admins.GET("/get_instance_loan", h.getInstanceLoan)
+	admins.GET("/get_book_holds", h.getBookHolds)
+	admins.GET("/pay_fine", h.payFine)
+	admins.GET("/waive_fine", h.waiveFine)
 
 	err := router.Run(address)
 	if err != nil {
@@ -61,44 +222,18 @@ func (h *Handler) main(ctx *gin.Context) {
 }
 
 func (h *Handler) returnBook(ctx *gin.Context) {
-	bookID := ctx.Query("book_id")
-
-	intBookID, err := strconv.Atoi(bookID)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte("internal server error"))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
-		return
+	instanceID := ctx.Query("instance_id")
+	if instanceID == "" {
+		// book_id is the former name of the parameter, it still holds an instance id
+		instanceID = ctx.Query("book_id")
 	}
 
-	err = h.s.ReturnBook(intBookID)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
+	intReaderID, ok := h.readerID(ctx)
+	if !ok {
 		return
 	}
 
-	ctx.Status(http.StatusOK)
-	_, err = ctx.Writer.Write([]byte("the book has been returned"))
-	if err != nil {
-		h.l.Errorf("response error: %v", err)
-		return
-	}
-}
-
-func (h *Handler) takeBook(ctx *gin.Context) {
-	bookID := ctx.Query("book_id")
-
-	intBookID, err := strconv.Atoi(bookID)
+	intInstanceID, err := strconv.Atoi(instanceID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		_, err = ctx.Writer.Write([]byte("internal server error"))
@@ -110,19 +245,7 @@ func (h *Handler) takeBook(ctx *gin.Context) {
 		return
 	}
 
-	book, err := h.s.TakeBook(intBookID)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
-		return
-	}
-
-	response, err := json.Marshal(book)
+	err = h.s.ReturnBook(intReaderID, intInstanceID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
@@ -135,55 +258,26 @@ func (h *Handler) takeBook(ctx *gin.Context) {
 	}
 
 	ctx.Status(http.StatusOK)
-	ctx.Header("content-type", "application/json")
-	_, err = ctx.Writer.Write(response)
+	_, err = ctx.Writer.Write([]byte("the book has been returned"))
 	if err != nil {
 		h.l.Errorf("response error: %v", err)
 		return
 	}
 }
 
-func (h *Handler) updateLoginStatus(ctx *gin.Context) {
-	loginStatus := ctx.Query("login_status")
-	userID := ctx.Query("user_id")
-
-	intUserID, err := strconv.Atoi(userID)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte("internal server error"))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
-		return
+func (h *Handler) takeBook(ctx *gin.Context) {
+	instanceID := ctx.Query("instance_id")
+	if instanceID == "" {
+		// book_id is the former name of the parameter, it still holds an instance id
+		instanceID = ctx.Query("book_id")
 	}
 
-	err = h.s.UpdateLoginStatus(intUserID, loginStatus)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
+	intReaderID, ok := h.readerID(ctx)
+	if !ok {
 		return
 	}
 
-	ctx.Status(http.StatusOK)
-	_, err = ctx.Writer.Write([]byte("login status has been updated"))
-	if err != nil {
-		h.l.Errorf("response error: %v", err)
-		return
-	}
-}
-
-func (h *Handler) banUser(ctx *gin.Context) {
-	userID := ctx.Query("user_id")
-	adminID := ctx.Query("admin_id")
-
-	intUserID, err := strconv.Atoi(userID)
+	intInstanceID, err := strconv.Atoi(instanceID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		_, err = ctx.Writer.Write([]byte("internal server error"))
@@ -195,10 +289,10 @@ func (h *Handler) banUser(ctx *gin.Context) {
 		return
 	}
 
-	intAdminID, err := strconv.Atoi(adminID)
+	book, err := h.s.TakeBook(intReaderID, intInstanceID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte("internal server error"))
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
 		if err != nil {
 			h.l.Errorf("response error: %v", err)
 			return
@@ -207,7 +301,7 @@ func (h *Handler) banUser(ctx *gin.Context) {
 		return
 	}
 
-	err = h.s.BanUser(intUserID, intAdminID)
+	response, err := json.Marshal(book)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
@@ -220,7 +314,8 @@ func (h *Handler) banUser(ctx *gin.Context) {
 	}
 
 	ctx.Status(http.StatusOK)
-	_, err = ctx.Writer.Write([]byte("user has been banned"))
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
 	if err != nil {
 		h.l.Errorf("response error: %v", err)
 		return
@@ -231,10 +326,10 @@ func (h *Handler) updateInstanceStatus(ctx *gin.Context) {
 	instanceStatus := ctx.Query("instance_status")
 	instanceID := ctx.Query("instance_id")
 
-	intInstanceStatus, err := strconv.Atoi(instanceStatus)
+	parsedInstanceStatus, err := domain.ParseInstanceStatus(instanceStatus)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte("internal server error"))
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
 		if err != nil {
 			h.l.Errorf("response error: %v", err)
 			return
@@ -255,7 +350,7 @@ func (h *Handler) updateInstanceStatus(ctx *gin.Context) {
 		return
 	}
 
-	err = h.s.UpdateInstanceStatus(intInstanceID, intInstanceStatus)
+	err = h.s.UpdateInstanceStatus(h.actor(ctx), intInstanceID, parsedInstanceStatus)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
@@ -346,17 +441,8 @@ func (h *Handler) checkAvailability(ctx *gin.Context) {
 }
 
 func (h *Handler) checkBorrowBooks(ctx *gin.Context) {
-	readerID := ctx.Query("reader_id")
-
-	intReaderID, err := strconv.Atoi(readerID)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-		_, err = ctx.Writer.Write([]byte("internal server error"))
-		if err != nil {
-			h.l.Errorf("response error: %v", err)
-			return
-		}
-
+	intReaderID, ok := h.readerID(ctx)
+	if !ok {
 		return
 	}
 
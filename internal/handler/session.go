@@ -0,0 +1,280 @@
+package book_inventory_system_handler
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	sessionCookie = "session"
+	sessionHeader = "X-Session-Token"
+)
+
+// sessionToken returns the session token of the request, sent as the
+// session cookie, in the X-Session-Token header or as a bearer token.
+func sessionToken(ctx *gin.Context) string {
+	token, err := ctx.Cookie(sessionCookie)
+	if err == nil && token != "" {
+		return token
+	}
+
+	token = ctx.GetHeader(sessionHeader)
+	if token != "" {
+		return token
+	}
+
+	bearer, ok := bearerToken(ctx)
+	if ok && !isSignedToken(bearer) {
+		return bearer
+	}
+
+	return ""
+}
+
+// clearSessionCookie tells the browser to drop the session cookie, if the
+// request came with one.
+func clearSessionCookie(ctx *gin.Context) {
+	if _, err := ctx.Cookie(sessionCookie); err != nil {
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	ctx.SetCookie(sessionCookie, "", -1, "/", "", false, true)
+}
+
+// login takes the name and the password as form values, so that they do not
+// end up in the access log as a query would.
+func (h *Handler) login(ctx *gin.Context) {
+	name := ctx.PostForm("name")
+	password := ctx.PostForm("password")
+
+	login, err := h.s.Login(name, password)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		ctx.Status(http.StatusUnauthorized)
+		_, err = ctx.Writer.Write([]byte(err.Error()))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(login)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	// the new cookie replaces the one of an ended session authenticate cleared
+	ctx.Writer.Header().Del("Set-Cookie")
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	ctx.SetCookie(sessionCookie, login.Token, int(time.Until(login.Session.ExpireDate).Seconds()), "/", "", false, true)
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) logout(ctx *gin.Context) {
+	token := sessionToken(ctx)
+	if token == "" {
+		ctx.Status(http.StatusUnauthorized)
+		_, err := ctx.Writer.Write([]byte("no session token"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	err := h.s.Logout(token)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	clearSessionCookie(ctx)
+	ctx.Status(http.StatusOK)
+	_, err = ctx.Writer.Write([]byte("you have been logged out"))
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getSession(ctx *gin.Context) {
+	token := sessionToken(ctx)
+	if token == "" {
+		ctx.Status(http.StatusUnauthorized)
+		_, err := ctx.Writer.Write([]byte("no session token"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	session, err := h.s.Authenticate(token)
+	if err != nil {
+		ctx.Status(http.StatusUnauthorized)
+		_, err = ctx.Writer.Write([]byte(err.Error()))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(session)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) getUserSessions(ctx *gin.Context) {
+	userID := ctx.Query("user_id")
+
+	intUserID, err := strconv.Atoi(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	principal, _ := h.principal(ctx)
+	sessions, err := h.s.GetUserSessions(principal, intUserID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(sessions)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
+
+func (h *Handler) revokeSession(ctx *gin.Context) {
+	sessionID := ctx.Query("session_id")
+
+	intSessionID, err := strconv.Atoi(sessionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte("internal server error"))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	session, err := h.s.RevokeSession(h.actor(ctx), intSessionID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	response, err := json.Marshal(session)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		_, err = ctx.Writer.Write([]byte(fmt.Sprintf("internal server error: %v", err)))
+		if err != nil {
+			h.l.Errorf("response error: %v", err)
+			return
+		}
+
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("content-type", "application/json")
+	_, err = ctx.Writer.Write(response)
+	if err != nil {
+		h.l.Errorf("response error: %v", err)
+		return
+	}
+}
@@ -0,0 +1,361 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (r *Repository) GetFunds() []domain.FundMapField {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	funds := make([]domain.FundMapField, 0, len(r.fund))
+	for _, fund := range r.fund {
+		funds = append(funds, fund)
+	}
+
+	sort.Slice(funds, func(i, j int) bool {
+		return funds[i].Name < funds[j].Name
+	})
+
+	return funds
+}
+
+func (r *Repository) CreateOrder(actor domain.Actor, productionID int, fundName string) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	production, ok := r.production[productionID]
+	if !ok {
+		return nil, fmt.Errorf("production not found")
+	}
+
+	if production.Archived {
+		return nil, fmt.Errorf("production is archived")
+	}
+
+	_, ok = r.fund[fundName]
+	if !ok {
+		return nil, fmt.Errorf("fund not found")
+	}
+
+	r.orderSeq++
+	order := domain.OrderMapField{
+		OrderID:      r.orderSeq,
+		ProductionID: productionID,
+		Fund:         fundName,
+		AdminID:      actor.UserID,
+		Status:       domain.OrderDraft,
+		Lines:        make([]domain.OrderLine, 0),
+		CreateDate:   time.Now(),
+	}
+	put(r, r.order, order.OrderID, order)
+
+	err := r.record(actor, "create_order", "order", order.OrderID, nil, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// AddOrderLine adds a book published by the production of the order to the
+// draft. UnitPrice is in minor currency units.
+func (r *Repository) AddOrderLine(actor domain.Actor, orderID, bookID, quantity, unitPrice int) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	order, ok := r.order[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	previous := order
+
+	if order.Status != domain.OrderDraft {
+		return nil, fmt.Errorf("order is %s", order.Status)
+	}
+
+	book, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	if book.Archived {
+		return nil, fmt.Errorf("book is archived")
+	}
+
+	if book.ProductionID != order.ProductionID {
+		return nil, fmt.Errorf("book is not published by the production of the order")
+	}
+
+	if quantity <= 0 || unitPrice < 0 {
+		return nil, fmt.Errorf("invalid quantity or price")
+	}
+
+	for _, line := range order.Lines {
+		if line.BookID == bookID {
+			return nil, fmt.Errorf("book is already on the order")
+		}
+	}
+
+	lines := make([]domain.OrderLine, len(order.Lines), len(order.Lines)+1)
+	copy(lines, order.Lines)
+	order.Lines = append(lines, domain.OrderLine{
+		BookID:    bookID,
+		Quantity:  quantity,
+		UnitPrice: unitPrice,
+	})
+	order.Total += quantity * unitPrice
+	put(r, r.order, orderID, order)
+
+	err := r.record(actor, "add_order_line", "order", orderID, previous, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// SendOrder sends the draft to the production and debits its total from the
+// fund of the order.
+func (r *Repository) SendOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	order, ok := r.order[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	previous := order
+
+	if order.Status != domain.OrderDraft {
+		return nil, fmt.Errorf("order is %s", order.Status)
+	}
+
+	if len(order.Lines) == 0 {
+		return nil, fmt.Errorf("order has no lines")
+	}
+
+	fund := r.fund[order.Fund]
+	if fund.Balance < order.Total {
+		return nil, fmt.Errorf("insufficient funds: %d left in %s", fund.Balance, fund.Name)
+	}
+
+	fund.Debited += order.Total
+	fund.Balance -= order.Total
+	put(r, r.fund, order.Fund, fund)
+
+	now := time.Now()
+	order.Status = domain.OrderSent
+	order.SendDate = &now
+	put(r, r.order, orderID, order)
+
+	err := r.record(actor, "send_order", "order", orderID, previous, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// ReceiveOrder records the delivery of copies of a book on the order. Every
+// delivered copy becomes a new instance at the default branch, available or
+// on the hold shelf when readers are waiting for the book.
+func (r *Repository) ReceiveOrder(actor domain.Actor, orderID, bookID, quantity int) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	order, ok := r.order[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	previous := order
+
+	if order.Status != domain.OrderSent && order.Status != domain.OrderPartiallyReceived {
+		return nil, fmt.Errorf("order is %s", order.Status)
+	}
+
+	index := -1
+	for i, line := range order.Lines {
+		if line.BookID == bookID {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return nil, fmt.Errorf("book is not on the order")
+	}
+
+	line := order.Lines[index]
+	if quantity <= 0 || quantity > line.Quantity-line.Received {
+		return nil, fmt.Errorf("invalid quantity: %d copies left to receive", line.Quantity-line.Received)
+	}
+
+	instanceIDs := make([]int, len(line.InstanceIDs), len(line.InstanceIDs)+quantity)
+	copy(instanceIDs, line.InstanceIDs)
+	line.InstanceIDs = instanceIDs
+	now := time.Now()
+	for i := 0; i < quantity; i++ {
+		r.instanceSeq++
+		instanceID := r.instanceSeq
+		put(r, r.instance, instanceID, domain.InstanceMapField{
+			BookID:          bookID,
+			Status:          domain.InstanceInTransit,
+			HomeBranchID:    r.defaultBranchID,
+			CurrentBranchID: r.defaultBranchID,
+		})
+
+		err := r.shelveInstance(instanceID, now)
+		if err != nil {
+			return nil, err
+		}
+
+		line.InstanceIDs = append(line.InstanceIDs, instanceID)
+	}
+	line.Received += quantity
+
+	lines := make([]domain.OrderLine, len(order.Lines))
+	copy(lines, order.Lines)
+	lines[index] = line
+	order.Lines = lines
+
+	order.Status = domain.OrderReceived
+	for _, line = range order.Lines {
+		if line.Received < line.Quantity {
+			order.Status = domain.OrderPartiallyReceived
+			break
+		}
+	}
+
+	if order.Status == domain.OrderReceived {
+		order.CloseDate = &now
+	}
+
+	put(r, r.order, orderID, order)
+
+	err := r.record(actor, "receive_order", "order", orderID, previous, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// CancelOrder cancels the order. For a sent order the price of the copies
+// that have not been received is credited back to the fund.
+func (r *Repository) CancelOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	order, ok := r.order[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	previous := order
+
+	if order.Status == domain.OrderReceived || order.Status == domain.OrderCancelled {
+		return nil, fmt.Errorf("order is %s", order.Status)
+	}
+
+	if order.Status != domain.OrderDraft {
+		refund := order.Outstanding()
+		fund := r.fund[order.Fund]
+		fund.Debited -= refund
+		fund.Balance += refund
+		put(r, r.fund, order.Fund, fund)
+	}
+
+	now := time.Now()
+	order.Status = domain.OrderCancelled
+	order.CloseDate = &now
+	put(r, r.order, orderID, order)
+
+	err := r.record(actor, "cancel_order", "order", orderID, previous, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *Repository) GetOrder(orderID int) (*domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.order[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	return &order, nil
+}
+
+func (r *Repository) GetProductionOrders(productionID int) ([]domain.OrderMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.production[productionID]
+	if !ok {
+		return nil, fmt.Errorf("production not found")
+	}
+
+	orders := make([]domain.OrderMapField, 0)
+	for _, order := range r.order {
+		if order.ProductionID == productionID {
+			orders = append(orders, order)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+
+	return orders, nil
+}
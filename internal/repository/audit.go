@@ -0,0 +1,44 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
+	"fmt"
+)
+
+// GetAuditLog returns the recorded privileged changes matching the filter,
+// oldest first.
+func (r *Repository) GetAuditLog(principal domain.Principal, filter audit.Filter) ([]audit.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[principal.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	if r.audit == nil {
+		return nil, fmt.Errorf("audit log is not configured")
+	}
+
+	return r.audit.Query(filter), nil
+}
+
+// record appends a privileged change to the audit log, if there is one. It is
+// called once the change is made, so that the log is written in the same
+// order as the changes, and ends the journal begun for the change when the
+// entry is written. When it fails the journal is left for the deferred
+// rollback, so that no change stands without its entry. The caller must hold
+// r.mu.
+func (r *Repository) record(actor domain.Actor, action, entity string, entityID int, before, after any) error {
+	if r.audit != nil {
+		_, err := r.audit.Append(actor.RequestID, actor.UserID, action, entity, entityID, before, after)
+		if err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+	}
+
+	r.journal = nil
+
+	return nil
+}
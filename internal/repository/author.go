@@ -0,0 +1,174 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (r *Repository) GetAuthor(authorID int) (*domain.AuthorEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	author, ok := r.author[authorID]
+	if !ok {
+		return nil, fmt.Errorf("author not found")
+	}
+
+	return &domain.AuthorEntry{
+		AuthorID:       authorID,
+		AuthorMapField: author,
+	}, nil
+}
+
+func (r *Repository) CreateAuthor(actor domain.Actor, author domain.AuthorMapField) (*domain.AuthorEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	r.authorSeq++
+	authorID := r.authorSeq
+	author, err := r.checkAuthor(authorID, author)
+	if err != nil {
+		return nil, err
+	}
+
+	put(r, r.author, authorID, author)
+
+	err = r.record(actor, "create_author", "author", authorID, nil, author)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthorEntry{
+		AuthorID:       authorID,
+		AuthorMapField: author,
+	}, nil
+}
+
+// UpdateAuthor replaces the author with the given one and refreshes the
+// search index of the books crediting them.
+func (r *Repository) UpdateAuthor(actor domain.Actor, authorID int, author domain.AuthorMapField) (*domain.AuthorEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.author[authorID]
+	if !ok {
+		return nil, fmt.Errorf("author not found")
+	}
+
+	author, err := r.checkAuthor(authorID, author)
+	if err != nil {
+		return nil, err
+	}
+
+	put(r, r.author, authorID, author)
+	for _, bookID := range r.authorBooks(authorID) {
+		r.indexBook(bookID)
+	}
+
+	err = r.record(actor, "update_author", "author", authorID, previous, author)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthorEntry{
+		AuthorID:       authorID,
+		AuthorMapField: author,
+	}, nil
+}
+
+// DeleteAuthor deletes the author or archives it, following the delete rule
+// of the books crediting the author.
+func (r *Repository) DeleteAuthor(actor domain.Actor, authorID int) (*domain.DeleteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.author[authorID]
+	if !ok {
+		return nil, fmt.Errorf("author not found")
+	}
+
+	r.expireHolds(time.Now())
+
+	d := newDeletion("author", authorID)
+	r.planAuthorDeletion(d, authorID)
+
+	result, err := d.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.record(actor, "delete_author", "author", authorID, previous, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkAuthor checks the required fields of the author and keeps its
+// archived flag. The caller must hold r.mu.
+func (r *Repository) checkAuthor(authorID int, author domain.AuthorMapField) (domain.AuthorMapField, error) {
+	if author.Name == "" || author.Surname == "" {
+		return author, fmt.Errorf("author name and surname are required")
+	}
+
+	previous := r.author[authorID]
+	author.Archived = previous.Archived
+
+	if author.ProductionID != 0 {
+		production, ok := r.production[author.ProductionID]
+		if !ok {
+			return author, fmt.Errorf("production %d not found", author.ProductionID)
+		}
+
+		if production.Archived && previous.ProductionID != author.ProductionID {
+			return author, fmt.Errorf("production %d is archived", author.ProductionID)
+		}
+	}
+
+	return author, nil
+}
+
+// authorBooks returns the ids of the books crediting the author in any role,
+// in ascending order. The caller must hold r.mu.
+func (r *Repository) authorBooks(authorID int) []int {
+	bookIDs := make([]int, 0)
+	for bookID, book := range r.books {
+		for _, author := range book.Authors {
+			if author.AuthorID == authorID {
+				bookIDs = append(bookIDs, bookID)
+				break
+			}
+		}
+	}
+
+	sort.Ints(bookIDs)
+
+	return bookIDs
+}
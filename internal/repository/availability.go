@@ -0,0 +1,176 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CheckBookAvailability counts the copies of the book at the branch, or in
+// the whole library when branchID is 0.
+func (r *Repository) CheckBookAvailability(bookID, branchID int) (*domain.BookAvailability, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	_, ok = r.branch[branchID]
+	if branchID != 0 && !ok {
+		return nil, fmt.Errorf("branch not found")
+	}
+
+	availability := r.bookAvailability(bookID, branchID)
+	return &availability, nil
+}
+
+func (r *Repository) GetBooksAvailability(branchID int) ([]domain.BookAvailability, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.branch[branchID]
+	if branchID != 0 && !ok {
+		return nil, fmt.Errorf("branch not found")
+	}
+
+	availability := make([]domain.BookAvailability, 0, len(r.books))
+	for bookID := range r.books {
+		availability = append(availability, r.bookAvailability(bookID, branchID))
+	}
+
+	sort.Slice(availability, func(i, j int) bool {
+		return availability[i].BookID < availability[j].BookID
+	})
+
+	return availability, nil
+}
+
+// FindAvailableInstance returns the copy of the book the reader would get:
+// the one kept on the hold shelf for the reader if any, otherwise the
+// available copy with the lowest id.
+func (r *Repository) FindAvailableInstance(readerID, bookID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.books[bookID]
+	if !ok {
+		return 0, fmt.Errorf("book not found")
+	}
+
+	instanceID, ok := r.availableInstance(readerID, bookID)
+	if !ok {
+		return 0, fmt.Errorf("no available instances")
+	}
+
+	return instanceID, nil
+}
+
+// TakeAnyInstance lends any available copy of the book to the reader on the
+// given terms.
+func (r *Repository) TakeAnyInstance(readerID, bookID int, terms domain.LoanTerms) (*domain.BookCheckout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	book, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	instanceID, ok := r.availableInstance(readerID, bookID)
+	if !ok {
+		return nil, fmt.Errorf("no available instances")
+	}
+
+	problems := r.checkoutProblems(readerID, instanceID, now)
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+
+	loan, err := r.takeInstance(readerID, instanceID, terms, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BookCheckout{
+		InstanceID: instanceID,
+		DueDate:    loan.DueDate,
+		Book:       book,
+	}, nil
+}
+
+// bookAvailability counts the copies of the book by status, only those at the
+// branch unless branchID is 0. The caller must hold r.mu.
+func (r *Repository) bookAvailability(bookID, branchID int) domain.BookAvailability {
+	availability := domain.BookAvailability{
+		BookID:   bookID,
+		BranchID: branchID,
+	}
+
+	for instanceID, instance := range r.instance {
+		if instance.BookID != bookID {
+			continue
+		}
+
+		if branchID != 0 && instance.CurrentBranchID != branchID {
+			continue
+		}
+
+		availability.Total++
+		switch instance.Status {
+		case domain.InstanceAvailable:
+			if _, ok := r.requestedTransferByInstance(instanceID); ok {
+				availability.Other++
+				continue
+			}
+
+			availability.Available++
+		case domain.InstanceOnLoan:
+			availability.OnLoan++
+		default:
+			availability.Other++
+		}
+	}
+
+	return availability
+}
+
+// availableInstance picks the copy of the book for the reader. The caller
+// must hold r.mu.
+func (r *Repository) availableInstance(readerID, bookID int) (int, bool) {
+	for _, hold := range r.sortedHolds() {
+		if hold.ReaderID == readerID && hold.BookID == bookID && hold.Status == domain.HoldReady {
+			return hold.InstanceID, true
+		}
+	}
+
+	instanceID := 0
+	for id, instance := range r.instance {
+		if instance.BookID != bookID || instance.Status != domain.InstanceAvailable {
+			continue
+		}
+
+		if _, ok := r.requestedTransferByInstance(id); ok {
+			continue
+		}
+
+		if instanceID == 0 || id < instanceID {
+			instanceID = id
+		}
+	}
+
+	return instanceID, instanceID != 0
+}
@@ -0,0 +1,170 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BanUser bans the user for the given duration, or until the ban is lifted
+// when the duration is zero. The user is logged out; the reader record and
+// the loans of the user are kept, so borrowed copies can still be returned.
+func (r *Repository) BanUser(actor domain.Actor, userID int, reason string, duration time.Duration) (*domain.BanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	now := time.Now()
+	r.expireBans(now)
+
+	user, ok := r.user[userID]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if user.Banned {
+		return nil, fmt.Errorf("user is already banned")
+	}
+
+	if reason == "" {
+		return nil, fmt.Errorf("ban reason is required")
+	}
+
+	if duration < 0 {
+		return nil, fmt.Errorf("invalid ban duration %v", duration)
+	}
+
+	r.banSeq++
+	ban := domain.BanMapField{
+		BanID:     r.banSeq,
+		UserID:    userID,
+		AdminID:   actor.UserID,
+		Reason:    reason,
+		Status:    domain.BanActive,
+		StartDate: now,
+	}
+
+	if duration > 0 {
+		endDate := now.Add(duration)
+		ban.EndDate = &endDate
+	}
+
+	put(r, r.ban, ban.BanID, ban)
+
+	user.Banned = true
+	put(r, r.user, userID, user)
+	r.endUserSessions(userID, now)
+
+	err := r.record(actor, "ban_user", "user", userID, nil, ban)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ban, nil
+}
+
+// UnbanUser lifts the active ban of the user before it ends.
+func (r *Repository) UnbanUser(actor domain.Actor, userID int, reason string) (*domain.BanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	now := time.Now()
+	r.expireBans(now)
+
+	user, ok := r.user[userID]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	for _, ban := range r.userBans(userID) {
+		if ban.Status != domain.BanActive {
+			continue
+		}
+
+		previous := ban
+		ban.Status = domain.BanLifted
+		ban.LiftAdminID = actor.UserID
+		ban.LiftReason = reason
+		ban.LiftDate = &now
+		put(r, r.ban, ban.BanID, ban)
+
+		user.Banned = false
+		put(r, r.user, userID, user)
+
+		err := r.record(actor, "unban_user", "user", userID, previous, ban)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ban, nil
+	}
+
+	return nil, fmt.Errorf("user is not banned")
+}
+
+// GetUserBans returns the ban history of the user, oldest first.
+func (r *Repository) GetUserBans(userID int) ([]domain.BanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireBans(time.Now())
+
+	_, ok := r.user[userID]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return r.userBans(userID), nil
+}
+
+// userBans returns the bans of the user ordered by id. The caller must hold
+// r.mu.
+func (r *Repository) userBans(userID int) []domain.BanMapField {
+	bans := make([]domain.BanMapField, 0)
+	for _, ban := range r.ban {
+		if ban.UserID == userID {
+			bans = append(bans, ban)
+		}
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].BanID < bans[j].BanID
+	})
+
+	return bans
+}
+
+// expireBans ends the active bans whose end date has passed. The caller must
+// hold r.mu.
+func (r *Repository) expireBans(now time.Time) {
+	for banID, ban := range r.ban {
+		if ban.Status != domain.BanActive || ban.EndDate == nil || ban.EndDate.After(now) {
+			continue
+		}
+
+		ban.Status = domain.BanExpired
+		put(r, r.ban, banID, ban)
+
+		user, ok := r.user[ban.UserID]
+		if ok {
+			user.Banned = false
+			put(r, r.user, ban.UserID, user)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	isbn "book-inventory-system/pkg/isbn"
+	"fmt"
+	"time"
+)
+
+// GetBookByISBN looks a book up by its ISBN-10 or ISBN-13.
+func (r *Repository) GetBookByISBN(code string) (*domain.BookEntry, error) {
+	normalized, err := isbn.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bookID, ok := r.isbn[normalized]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	return &domain.BookEntry{
+		BookID:       bookID,
+		BookMapField: r.books[bookID],
+	}, nil
+}
+
+func (r *Repository) GetBook(bookID int) (*domain.BookEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	book, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	return &domain.BookEntry{
+		BookID:       bookID,
+		BookMapField: book,
+	}, nil
+}
+
+func (r *Repository) CreateBook(actor domain.Actor, book domain.BookMapField) (*domain.BookEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	r.bookSeq++
+	bookID := r.bookSeq
+	book, err := r.checkBook(bookID, book)
+	if err != nil {
+		return nil, err
+	}
+
+	r.putBook(bookID, book)
+
+	err = r.record(actor, "create_book", "book", bookID, nil, book)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BookEntry{
+		BookID:       bookID,
+		BookMapField: book,
+	}, nil
+}
+
+// UpdateBook replaces the book with the given one.
+func (r *Repository) UpdateBook(actor domain.Actor, bookID int, book domain.BookMapField) (*domain.BookEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	book, err := r.checkBook(bookID, book)
+	if err != nil {
+		return nil, err
+	}
+
+	r.putBook(bookID, book)
+
+	err = r.record(actor, "update_book", "book", bookID, previous, book)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BookEntry{
+		BookID:       bookID,
+		BookMapField: book,
+	}, nil
+}
+
+// DeleteBook deletes the book or archives it, following the delete rules of
+// its instances, active holds and open purchase orders.
+func (r *Repository) DeleteBook(actor domain.Actor, bookID int) (*domain.DeleteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	r.expireHolds(time.Now())
+
+	d := newDeletion("book", bookID)
+	r.planBookDeletion(d, bookID, "")
+
+	result, err := d.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.record(actor, "delete_book", "book", bookID, previous, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkBook normalizes the book and checks that its required fields are set
+// and that everything it refers to exists. The caller must hold r.mu.
+func (r *Repository) checkBook(bookID int, book domain.BookMapField) (domain.BookMapField, error) {
+	if book.Name == "" {
+		return book, fmt.Errorf("book name is required")
+	}
+
+	if len(book.Authors) == 0 {
+		return book, fmt.Errorf("book needs at least one author")
+	}
+
+	if len(book.GenreIDs) == 0 {
+		return book, fmt.Errorf("book needs at least one genre")
+	}
+
+	book, err := r.normalizeBook(bookID, book)
+	if err != nil {
+		return book, err
+	}
+
+	// Archived entities keep the references a book already has but cannot
+	// be given to it anew.
+	previous := r.books[bookID]
+	book.Archived = previous.Archived
+
+	previousAuthors := make(map[int]bool)
+	for _, author := range previous.Authors {
+		previousAuthors[author.AuthorID] = true
+	}
+
+	for _, bookAuthor := range book.Authors {
+		author, ok := r.author[bookAuthor.AuthorID]
+		if !ok {
+			return book, fmt.Errorf("author %d not found", bookAuthor.AuthorID)
+		}
+
+		if author.Archived && !previousAuthors[bookAuthor.AuthorID] {
+			return book, fmt.Errorf("author %d is archived", bookAuthor.AuthorID)
+		}
+	}
+
+	previousGenres := make(map[int]bool)
+	for _, genreID := range previous.GenreIDs {
+		previousGenres[genreID] = true
+	}
+
+	for _, genreID := range book.GenreIDs {
+		genre, ok := r.genres[genreID]
+		if !ok {
+			return book, fmt.Errorf("genre %d not found", genreID)
+		}
+
+		if genre.Archived && !previousGenres[genreID] {
+			return book, fmt.Errorf("genre %d is archived", genreID)
+		}
+	}
+
+	production, ok := r.production[book.ProductionID]
+	if !ok {
+		return book, fmt.Errorf("production %d not found", book.ProductionID)
+	}
+
+	if production.Archived && previous.ProductionID != book.ProductionID {
+		return book, fmt.Errorf("production %d is archived", book.ProductionID)
+	}
+
+	language, ok := r.language[book.LanguageID]
+	if !ok {
+		return book, fmt.Errorf("language %d not found", book.LanguageID)
+	}
+
+	if language.Archived && previous.LanguageID != book.LanguageID {
+		return book, fmt.Errorf("language %d is archived", book.LanguageID)
+	}
+
+	return book, nil
+}
+
+// normalizeBook fills in the default category and author role, normalizes
+// the ISBN and checks the fields that do not refer to other entities, apart
+// from the series. The caller must hold r.mu.
+func (r *Repository) normalizeBook(bookID int, book domain.BookMapField) (domain.BookMapField, error) {
+	if book.Category == "" {
+		book.Category = r.defaultItemCategory
+	}
+
+	authors := make([]domain.BookAuthor, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		if author.Role == "" {
+			author.Role = domain.AuthorRoleAuthor
+		}
+
+		if !domain.IsValidAuthorRole(author.Role) {
+			return book, fmt.Errorf("unknown author role %q", author.Role)
+		}
+
+		authors = append(authors, author)
+	}
+
+	book.Authors = authors
+	book.GenreIDs = append(make([]int, 0, len(book.GenreIDs)), book.GenreIDs...)
+
+	if book.ISBN != "" {
+		var err error
+		book.ISBN, err = isbn.Normalize(book.ISBN)
+		if err != nil {
+			return book, err
+		}
+
+		if otherID, ok := r.isbn[book.ISBN]; ok && otherID != bookID {
+			return book, fmt.Errorf("isbn %s is already used by book %d", book.ISBN, otherID)
+		}
+	}
+
+	if !domain.IsValidBookFormat(book.Format) {
+		return book, fmt.Errorf("unknown format %q", book.Format)
+	}
+
+	if book.PublicationYear < 0 || book.Edition < 0 || book.PageCount < 0 {
+		return book, fmt.Errorf("publication year, edition and page count cannot be negative")
+	}
+
+	if book.SeriesID != 0 {
+		if _, ok := r.series[book.SeriesID]; !ok {
+			return book, fmt.Errorf("series %d not found", book.SeriesID)
+		}
+
+		if book.Volume <= 0 {
+			return book, fmt.Errorf("invalid volume %d", book.Volume)
+		}
+	}
+
+	return book, nil
+}
+
+// putBook stores the book and updates the ISBN and search indexes. The caller
+// must hold r.mu.
+func (r *Repository) putBook(bookID int, book domain.BookMapField) {
+	if old, ok := r.books[bookID]; ok && old.ISBN != "" {
+		remove(r, r.isbn, old.ISBN)
+	}
+
+	put(r, r.books, bookID, book)
+	if book.ISBN != "" {
+		put(r, r.isbn, book.ISBN, bookID)
+	}
+
+	r.indexBook(bookID)
+}
+
+// maxMapID returns the largest id of the map, zero when it is empty.
+func maxMapID[T any](m map[int]T) int {
+	maxID := 0
+	for id := range m {
+		if id > maxID {
+			maxID = id
+		}
+	}
+
+	return maxID
+}
@@ -0,0 +1,336 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (r *Repository) GetBranches() map[int]domain.BranchMapField {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	branches := make(map[int]domain.BranchMapField, len(r.branch))
+	for branchID, branch := range r.branch {
+		branches[branchID] = branch
+	}
+
+	return branches
+}
+
+// RequestTransfer reserves a copy of the book available at another branch to
+// be sent to the branch the reader wants to pick it up at.
+func (r *Repository) RequestTransfer(readerID, bookID, branchID int) (*domain.TransferMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	err := r.checkReader(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	_, ok = r.branch[branchID]
+	if !ok {
+		return nil, fmt.Errorf("branch not found")
+	}
+
+	instanceID := 0
+	for id, instance := range r.instance {
+		if instance.BookID != bookID || instance.Status != domain.InstanceAvailable {
+			continue
+		}
+
+		if _, ok = r.requestedTransferByInstance(id); ok {
+			continue
+		}
+
+		if instance.CurrentBranchID == branchID {
+			return nil, fmt.Errorf("book is available at the branch")
+		}
+
+		if instanceID == 0 || id < instanceID {
+			instanceID = id
+		}
+	}
+
+	if instanceID == 0 {
+		return nil, fmt.Errorf("no available instances at other branches")
+	}
+
+	r.transferSeq++
+	transfer := domain.TransferMapField{
+		TransferID:   r.transferSeq,
+		InstanceID:   instanceID,
+		BookID:       bookID,
+		FromBranchID: r.instance[instanceID].CurrentBranchID,
+		ToBranchID:   branchID,
+		ReaderID:     readerID,
+		Status:       domain.TransferRequested,
+		RequestDate:  time.Now(),
+	}
+	put(r, r.transfer, transfer.TransferID, transfer)
+
+	return &transfer, nil
+}
+
+// DispatchTransfer sends the reserved copy on its way.
+func (r *Repository) DispatchTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	transfer, ok := r.transfer[transferID]
+	if !ok {
+		return nil, fmt.Errorf("transfer not found")
+	}
+
+	if transfer.Status != domain.TransferRequested {
+		return nil, fmt.Errorf("transfer is %s", transfer.Status)
+	}
+
+	if status := r.instance[transfer.InstanceID].Status; status != domain.InstanceAvailable {
+		return nil, r.failTransfer(actor, transfer, status)
+	}
+
+	previous := transfer
+	err := r.setInstanceStatus(transfer.InstanceID, domain.InstanceInTransit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer.Status = domain.TransferInTransit
+	transfer.DispatchDate = &now
+	put(r, r.transfer, transferID, transfer)
+
+	err = r.record(actor, "dispatch_transfer", "transfer", transferID, previous, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// ReceiveTransfer records the arrival of the copy at the destination branch,
+// where it is put on the hold shelf for the reader who requested it. A copy
+// returning to its home branch is shelved there like a returned one.
+func (r *Repository) ReceiveTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	transfer, ok := r.transfer[transferID]
+	if !ok {
+		return nil, fmt.Errorf("transfer not found")
+	}
+
+	if transfer.Status != domain.TransferInTransit {
+		return nil, fmt.Errorf("transfer is %s", transfer.Status)
+	}
+
+	if status := r.instance[transfer.InstanceID].Status; status != domain.InstanceInTransit {
+		return nil, r.failTransfer(actor, transfer, status)
+	}
+
+	previous := transfer
+	now := time.Now()
+
+	transfer.Status = domain.TransferReceived
+	transfer.ReceiveDate = &now
+	put(r, r.transfer, transferID, transfer)
+
+	instance := r.instance[transfer.InstanceID]
+	instance.CurrentBranchID = transfer.ToBranchID
+	put(r, r.instance, transfer.InstanceID, instance)
+
+	if transfer.ReaderID != 0 {
+		err := r.setInstanceStatus(transfer.InstanceID, domain.InstanceOnHoldShelf)
+		if err != nil {
+			return nil, err
+		}
+
+		expireDate := now.Add(r.holdPickupWindow)
+		r.holdSeq++
+		put(r, r.hold, r.holdSeq, domain.HoldMapField{
+			HoldID:     r.holdSeq,
+			ReaderID:   transfer.ReaderID,
+			BookID:     transfer.BookID,
+			Status:     domain.HoldReady,
+			PlacedDate: transfer.RequestDate,
+			InstanceID: transfer.InstanceID,
+			ReadyDate:  &now,
+			ExpireDate: &expireDate,
+		})
+	} else {
+		err := r.shelveInstance(transfer.InstanceID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.record(actor, "receive_transfer", "transfer", transferID, previous, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// CancelTransfer withdraws a transfer request that has not been dispatched.
+func (r *Repository) CancelTransfer(transferID, readerID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, ok := r.transfer[transferID]
+	if !ok || transfer.ReaderID != readerID {
+		return fmt.Errorf("transfer not found")
+	}
+
+	if transfer.Status != domain.TransferRequested {
+		return fmt.Errorf("transfer is %s", transfer.Status)
+	}
+
+	r.cancelTransfer(transferID, time.Now())
+
+	return nil
+}
+
+func (r *Repository) GetTransfer(transferID int) (*domain.TransferMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, ok := r.transfer[transferID]
+	if !ok {
+		return nil, fmt.Errorf("transfer not found")
+	}
+
+	return &transfer, nil
+}
+
+// GetBranchTransfers returns the transfers from or to the branch.
+func (r *Repository) GetBranchTransfers(branchID int) ([]domain.TransferMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.branch[branchID]
+	if !ok {
+		return nil, fmt.Errorf("branch not found")
+	}
+
+	transfers := make([]domain.TransferMapField, 0)
+	for _, transfer := range r.transfer {
+		if transfer.FromBranchID == branchID || transfer.ToBranchID == branchID {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].TransferID < transfers[j].TransferID
+	})
+
+	return transfers, nil
+}
+
+// requestedTransferByInstance returns the id of the transfer the copy is
+// reserved for. The caller must hold r.mu.
+func (r *Repository) requestedTransferByInstance(instanceID int) (int, bool) {
+	for transferID, transfer := range r.transfer {
+		if transfer.InstanceID == instanceID && transfer.Status == domain.TransferRequested {
+			return transferID, true
+		}
+	}
+
+	return 0, false
+}
+
+// transferInTransitByInstance returns the id of the transfer carrying the
+// copy. The caller must hold r.mu.
+func (r *Repository) transferInTransitByInstance(instanceID int) (int, bool) {
+	for transferID, transfer := range r.transfer {
+		if transfer.InstanceID == instanceID && transfer.Status == domain.TransferInTransit {
+			return transferID, true
+		}
+	}
+
+	return 0, false
+}
+
+// cancelTransfer closes the transfer without the copy arriving. The caller
+// must hold r.mu.
+func (r *Repository) cancelTransfer(transferID int, now time.Time) {
+	transfer := r.transfer[transferID]
+	transfer.Status = domain.TransferCancelled
+	transfer.CancelDate = &now
+	put(r, r.transfer, transferID, transfer)
+}
+
+// cancelRequestedTransfer withdraws the transfer the copy is reserved for, as
+// it left the shelf other than by being dispatched. The caller must hold
+// r.mu.
+func (r *Repository) cancelRequestedTransfer(instanceID int, now time.Time) {
+	if transferID, ok := r.requestedTransferByInstance(instanceID); ok {
+		r.cancelTransfer(transferID, now)
+	}
+}
+
+// failTransfer cancels a transfer whose copy is no longer in the status the
+// transfer left it in and reports why. The cancellation is recorded and
+// stands, though the dispatch or receipt it stopped fails. The caller must
+// hold r.mu.
+func (r *Repository) failTransfer(actor domain.Actor, transfer domain.TransferMapField, status domain.InstanceStatus) error {
+	r.cancelTransfer(transfer.TransferID, time.Now())
+
+	err := r.record(actor, "cancel_transfer", "transfer", transfer.TransferID, transfer, r.transfer[transfer.TransferID])
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("instance is %s, the transfer is cancelled", status)
+}
+
+// returnHome requests a transfer taking a copy on the shelf of another branch
+// back to its home branch. The copy is reserved for it until it is
+// dispatched. The caller must hold r.mu.
+func (r *Repository) returnHome(instanceID int, now time.Time) {
+	instance := r.instance[instanceID]
+	if instance.HomeBranchID == 0 || instance.CurrentBranchID == instance.HomeBranchID {
+		return
+	}
+
+	if _, ok := r.requestedTransferByInstance(instanceID); ok {
+		return
+	}
+
+	r.transferSeq++
+	put(r, r.transfer, r.transferSeq, domain.TransferMapField{
+		TransferID:   r.transferSeq,
+		InstanceID:   instanceID,
+		BookID:       instance.BookID,
+		FromBranchID: instance.CurrentBranchID,
+		ToBranchID:   instance.HomeBranchID,
+		Status:       domain.TransferRequested,
+		RequestDate:  now,
+	})
+}
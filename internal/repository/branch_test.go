@@ -0,0 +1,219 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestTransferToReader(t *testing.T) {
+	r := newTestRepository(t)
+	instanceID, transferID := dispatch(t, r, testReaderID)
+
+	if status := r.instance[instanceID].Status; status != domain.InstanceInTransit {
+		t.Fatalf("dispatched copy is %s, want %s", status, domain.InstanceInTransit)
+	}
+
+	transfer, err := r.ReceiveTransfer(testAdmin, transferID)
+	if err != nil {
+		t.Fatalf("ReceiveTransfer: %v", err)
+	}
+
+	if transfer.Status != domain.TransferReceived {
+		t.Errorf("transfer is %s, want %s", transfer.Status, domain.TransferReceived)
+	}
+
+	instance := r.instance[instanceID]
+	if instance.Status != domain.InstanceOnHoldShelf || instance.CurrentBranchID != testOtherBranchID {
+		t.Errorf("received copy = %+v, want it on the hold shelf of branch %d", instance, testOtherBranchID)
+	}
+
+	holdID, ok := r.readyHoldByInstance(instanceID)
+	if !ok || r.hold[holdID].ReaderID != testReaderID {
+		t.Fatalf("no hold is ready for reader %d", testReaderID)
+	}
+
+	if _, err = r.TakeBook(testOtherID, instanceID, testTerms); err == nil {
+		t.Error("another reader took the copy kept for the reader")
+	}
+
+	if _, err = r.TakeBook(testReaderID, instanceID, testTerms); err != nil {
+		t.Errorf("TakeBook: %v", err)
+	}
+}
+
+func TestTransferHome(t *testing.T) {
+	r := newTestRepository(t)
+	instanceID := addInstance(r, domain.InstanceAvailable, testOtherBranchID)
+	if _, err := r.TakeBook(testReaderID, instanceID, testTerms); err != nil {
+		t.Fatalf("TakeBook: %v", err)
+	}
+
+	if err := r.ReturnBook(testReaderID, instanceID); err != nil {
+		t.Fatalf("ReturnBook: %v", err)
+	}
+
+	transferID, ok := r.requestedTransferByInstance(instanceID)
+	if !ok {
+		t.Fatal("copy returned away from home is not sent home")
+	}
+
+	transfer := r.transfer[transferID]
+	if transfer.ReaderID != 0 || transfer.FromBranchID != testOtherBranchID || transfer.ToBranchID != testHomeBranchID {
+		t.Errorf("transfer = %+v, want one home without a reader", transfer)
+	}
+
+	if _, err := r.DispatchTransfer(testAdmin, transferID); err != nil {
+		t.Fatalf("DispatchTransfer: %v", err)
+	}
+
+	if _, err := r.ReceiveTransfer(testAdmin, transferID); err != nil {
+		t.Fatalf("ReceiveTransfer: %v", err)
+	}
+
+	instance := r.instance[instanceID]
+	if instance.Status != domain.InstanceAvailable || instance.CurrentBranchID != testHomeBranchID {
+		t.Errorf("copy = %+v, want it available at home", instance)
+	}
+
+	if _, ok = r.requestedTransferByInstance(instanceID); ok {
+		t.Error("copy at home is sent home again")
+	}
+}
+
+func TestTransferOfChangedCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		// change moves the copy behind the back of the transfer
+		change   func(r *Repository, instanceID int)
+		dispatch bool
+		step     func(r *Repository, transferID int) error
+	}{
+		{
+			name: "dispatch of a copy in repair",
+			change: func(r *Repository, instanceID int) {
+				instance := r.instance[instanceID]
+				instance.Status = domain.InstanceInRepair
+				r.instance[instanceID] = instance
+			},
+			step: func(r *Repository, transferID int) error {
+				_, err := r.DispatchTransfer(testAdmin, transferID)
+				return err
+			},
+		},
+		{
+			name:     "receipt of a copy on loan",
+			dispatch: true,
+			change: func(r *Repository, instanceID int) {
+				instance := r.instance[instanceID]
+				instance.Status = domain.InstanceOnLoan
+				r.instance[instanceID] = instance
+				r.openLoan(testOtherID, instanceID, time.Now(), testTerms)
+			},
+			step: func(r *Repository, transferID int) error {
+				_, err := r.ReceiveTransfer(testAdmin, transferID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t)
+			instanceID := addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			transfer, err := r.RequestTransfer(testReaderID, testBookID, testOtherBranchID)
+			if err != nil {
+				t.Fatalf("RequestTransfer: %v", err)
+			}
+
+			if tt.dispatch {
+				if _, err = r.DispatchTransfer(testAdmin, transfer.TransferID); err != nil {
+					t.Fatalf("DispatchTransfer: %v", err)
+				}
+			}
+
+			tt.change(r, instanceID)
+			status := r.instance[instanceID].Status
+
+			if err = tt.step(r, transfer.TransferID); err == nil {
+				t.Fatal("transfer went on with a changed copy")
+			}
+
+			if got := r.transfer[transfer.TransferID].Status; got != domain.TransferCancelled {
+				t.Errorf("transfer is %s, want %s", got, domain.TransferCancelled)
+			}
+
+			if got := r.instance[instanceID].Status; got != status {
+				t.Errorf("copy is %s, want it left %s", got, status)
+			}
+
+			if len(r.hold) != 0 {
+				t.Errorf("holds %v were made for a failed transfer", r.hold)
+			}
+		})
+	}
+}
+
+func TestLostCopyCancelsTransfer(t *testing.T) {
+	tests := []struct {
+		name string
+		lose func(t *testing.T, r *Repository, instanceID int)
+	}{
+		{
+			name: "declared lost",
+			lose: func(t *testing.T, r *Repository, instanceID int) {
+				if err := r.UpdateInstanceStatus(testAdmin, instanceID, domain.InstanceLost); err != nil {
+					t.Fatalf("UpdateInstanceStatus: %v", err)
+				}
+			},
+		},
+		{
+			name: "sent to repair",
+			lose: func(t *testing.T, r *Repository, instanceID int) {
+				if err := r.UpdateInstanceStatus(testAdmin, instanceID, domain.InstanceInRepair); err != nil {
+					t.Fatalf("UpdateInstanceStatus: %v", err)
+				}
+			},
+		},
+		{
+			name: "missing from a stocktake",
+			lose: func(t *testing.T, r *Repository, instanceID int) {
+				stocktake, err := r.OpenStocktake(testAdmin, r.instance[instanceID].CurrentBranchID)
+				if err != nil {
+					t.Fatalf("OpenStocktake: %v", err)
+				}
+
+				if _, err = r.CloseStocktake(testAdmin, stocktake.StocktakeID, true); err != nil {
+					t.Fatalf("CloseStocktake: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t)
+
+			// returned away from home, the copy is reserved to be sent home
+			instanceID := addInstance(r, domain.InstanceAvailable, testOtherBranchID)
+			if _, err := r.TakeBook(testReaderID, instanceID, testTerms); err != nil {
+				t.Fatalf("TakeBook: %v", err)
+			}
+
+			if err := r.ReturnBook(testReaderID, instanceID); err != nil {
+				t.Fatalf("ReturnBook: %v", err)
+			}
+
+			transferID, ok := r.requestedTransferByInstance(instanceID)
+			if !ok {
+				t.Fatal("copy is not sent home")
+			}
+
+			tt.lose(t, r, instanceID)
+
+			if got := r.transfer[transferID].Status; got != domain.TransferCancelled {
+				t.Errorf("transfer is %s, want %s", got, domain.TransferCancelled)
+			}
+		})
+	}
+}
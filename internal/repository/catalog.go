@@ -0,0 +1,254 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	facetGenre = iota
+	facetLanguage
+	facetProduction
+	facetAuthor
+	facetAvailability
+)
+
+// GetCatalog lists the books matching the filter ordered by id, with the
+// number of books per genre, language, production, author and availability.
+func (r *Repository) GetCatalog(filter domain.CatalogFilter) (*domain.CatalogListing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	err := r.checkCatalogFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	bookIDs := make([]int, 0, len(r.books))
+	for bookID, book := range r.books {
+		if !book.Archived {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	sort.Ints(bookIDs)
+
+	listing := domain.CatalogListing{
+		Books: make([]domain.CatalogBook, 0),
+	}
+
+	genres := make(map[int]int)
+	languages := make(map[int]int)
+	productions := make(map[int]int)
+	authors := make(map[int]int)
+	for _, bookID := range bookIDs {
+		book := r.books[bookID]
+		availability := r.bookAvailability(bookID, filter.BranchID)
+		misses := catalogMisses(book, availability, filter)
+
+		if len(misses) == 0 {
+			listing.Books = append(listing.Books, domain.CatalogBook{
+				BookEntry: domain.BookEntry{
+					BookID:       bookID,
+					BookMapField: book,
+				},
+				Availability: availability,
+			})
+		}
+
+		// A book counts towards a facet when the facet is the only one it
+		// misses, or when it misses none.
+		counts := func(facet int) bool {
+			return len(misses) == 0 || (len(misses) == 1 && misses[0] == facet)
+		}
+
+		if counts(facetGenre) {
+			for _, genreID := range book.GenreIDs {
+				genres[genreID]++
+			}
+		}
+
+		if counts(facetLanguage) {
+			languages[book.LanguageID]++
+		}
+
+		if counts(facetProduction) {
+			productions[book.ProductionID]++
+		}
+
+		if counts(facetAuthor) {
+			seen := make(map[int]struct{}, len(book.Authors))
+			for _, author := range book.Authors {
+				if _, ok := seen[author.AuthorID]; !ok {
+					seen[author.AuthorID] = struct{}{}
+					authors[author.AuthorID]++
+				}
+			}
+		}
+
+		if counts(facetAvailability) {
+			if availability.Available > 0 {
+				listing.Facets.Availability.Available++
+			} else {
+				listing.Facets.Availability.Unavailable++
+			}
+		}
+	}
+
+	listing.Total = len(listing.Books)
+	listing.Facets.Genres = facetCounts(genres, filter.GenreIDs, func(id int) (string, bool) {
+		genre, ok := r.genres[id]
+		return genre.Name, ok
+	})
+	listing.Facets.Languages = facetCounts(languages, filter.LanguageIDs, func(id int) (string, bool) {
+		language, ok := r.language[id]
+		return language.Name, ok
+	})
+	listing.Facets.Productions = facetCounts(productions, filter.ProductionIDs, func(id int) (string, bool) {
+		production, ok := r.production[id]
+		return production.Name, ok
+	})
+	listing.Facets.Authors = facetCounts(authors, filter.AuthorIDs, func(id int) (string, bool) {
+		author, ok := r.author[id]
+		return authorFullName(author), ok
+	})
+
+	return &listing, nil
+}
+
+// checkCatalogFilter checks that the filter refers to existing entities. The
+// caller must hold r.mu.
+func (r *Repository) checkCatalogFilter(filter domain.CatalogFilter) error {
+	for _, genreID := range filter.GenreIDs {
+		if _, ok := r.genres[genreID]; !ok {
+			return fmt.Errorf("genre %d not found", genreID)
+		}
+	}
+
+	for _, languageID := range filter.LanguageIDs {
+		if _, ok := r.language[languageID]; !ok {
+			return fmt.Errorf("language %d not found", languageID)
+		}
+	}
+
+	for _, productionID := range filter.ProductionIDs {
+		if _, ok := r.production[productionID]; !ok {
+			return fmt.Errorf("production %d not found", productionID)
+		}
+	}
+
+	for _, authorID := range filter.AuthorIDs {
+		if _, ok := r.author[authorID]; !ok {
+			return fmt.Errorf("author %d not found", authorID)
+		}
+	}
+
+	if filter.BranchID != 0 {
+		if _, ok := r.branch[filter.BranchID]; !ok {
+			return fmt.Errorf("branch not found")
+		}
+	}
+
+	return nil
+}
+
+// catalogMisses returns the facets of the filter the book does not match.
+func catalogMisses(book domain.BookMapField, availability domain.BookAvailability, filter domain.CatalogFilter) []int {
+	misses := make([]int, 0)
+
+	if !matchesAny(filter.GenreIDs, book.GenreIDs) {
+		misses = append(misses, facetGenre)
+	}
+
+	if !matchesAny(filter.LanguageIDs, []int{book.LanguageID}) {
+		misses = append(misses, facetLanguage)
+	}
+
+	if !matchesAny(filter.ProductionIDs, []int{book.ProductionID}) {
+		misses = append(misses, facetProduction)
+	}
+
+	authorIDs := make([]int, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		authorIDs = append(authorIDs, author.AuthorID)
+	}
+
+	if !matchesAny(filter.AuthorIDs, authorIDs) {
+		misses = append(misses, facetAuthor)
+	}
+
+	if filter.Available != nil && *filter.Available != (availability.Available > 0) {
+		misses = append(misses, facetAvailability)
+	}
+
+	return misses
+}
+
+// matchesAny reports whether one of the ids is wanted. No wanted ids match
+// anything.
+func matchesAny(wanted, ids []int) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+
+	for _, want := range wanted {
+		for _, id := range ids {
+			if id == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// facetCounts resolves the counted ids to names, most frequent first. Ids
+// selected by the filter are listed even when no book has them, ids unknown
+// to the repository are left out.
+func facetCounts(counts map[int]int, selected []int, name func(id int) (string, bool)) []domain.FacetCount {
+	for _, id := range selected {
+		if _, ok := counts[id]; !ok {
+			counts[id] = 0
+		}
+	}
+
+	facet := make([]domain.FacetCount, 0, len(counts))
+	for id, count := range counts {
+		facetName, ok := name(id)
+		if !ok {
+			continue
+		}
+
+		facet = append(facet, domain.FacetCount{
+			ID:    id,
+			Name:  facetName,
+			Count: count,
+		})
+	}
+
+	sort.Slice(facet, func(i, j int) bool {
+		if facet[i].Count != facet[j].Count {
+			return facet[i].Count > facet[j].Count
+		}
+
+		return facet[i].ID < facet[j].ID
+	})
+
+	return facet
+}
+
+func authorFullName(author domain.AuthorMapField) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{author.Surname, author.Name, author.Patronymic} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
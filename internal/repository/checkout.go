@@ -0,0 +1,100 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"time"
+)
+
+func (r *Repository) GetCheckoutContext(readerID, instanceID int) (*domain.CheckoutContext, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	reader, ok := r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	instance, ok := r.instance[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found")
+	}
+
+	book, ok := r.books[instance.BookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	return &domain.CheckoutContext{
+		ReaderID:       readerID,
+		InstanceID:     instanceID,
+		BookID:         instance.BookID,
+		ReaderCategory: reader.Category,
+		ItemCategory:   book.Category,
+		OpenLoans:      r.openLoansInCategory(readerID, book.Category),
+		Problems:       r.checkoutProblems(readerID, instanceID, now),
+	}, nil
+}
+
+// checkoutProblems lists the reasons the reader cannot take the instance that
+// do not depend on the lending policy. The caller must hold r.mu.
+func (r *Repository) checkoutProblems(readerID, instanceID int, now time.Time) []string {
+	problems := make([]string, 0)
+
+	err := r.checkReader(readerID)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	account := r.fineAccount(readerID, now)
+	if account.Balance > r.fineThreshold {
+		problems = append(problems, fmt.Sprintf("outstanding fines %d exceed the limit %d", account.Balance, r.fineThreshold))
+	}
+
+	instance, ok := r.instance[instanceID]
+	if !ok {
+		return append(problems, "instance not found")
+	}
+
+	_, ok = r.books[instance.BookID]
+	if !ok {
+		return append(problems, "instance not found")
+	}
+
+	switch instance.Status {
+	case domain.InstanceAvailable:
+		if _, ok = r.requestedTransferByInstance(instanceID); ok {
+			problems = append(problems, "instance is reserved for a transfer")
+		}
+
+		if holdID, ok := r.firstWaitingHold(instance.BookID); ok && r.hold[holdID].ReaderID != readerID {
+			problems = append(problems, "book is held for another reader")
+		}
+	case domain.InstanceOnHoldShelf:
+		holdID, ok := r.readyHoldByInstance(instanceID)
+		if !ok || r.hold[holdID].ReaderID != readerID {
+			problems = append(problems, "instance is on hold for another reader")
+		}
+	default:
+		problems = append(problems, "you can`t take an instance")
+	}
+
+	return problems
+}
+
+// openLoansInCategory counts the open loans of the reader for items of the
+// category. The caller must hold r.mu.
+func (r *Repository) openLoansInCategory(readerID int, itemCategory string) int {
+	var counter int
+
+	for _, loan := range r.loan {
+		if loan.ReaderID == readerID && loan.IsOpen() && loan.ItemCategory == itemCategory {
+			counter++
+		}
+	}
+
+	return counter
+}
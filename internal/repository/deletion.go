@@ -0,0 +1,262 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// deletion collects what deleting an entity does before anything is
+// changed, so that a conflict anywhere down a cascade leaves the repository
+// untouched. It lives only while r.mu is held.
+type deletion struct {
+	entity string
+	id     int
+	now    time.Time
+
+	books map[int]bool // book id to whether it is archived
+
+	conflicts []domain.Reference
+	result    domain.DeleteResult
+	changes   []func()
+}
+
+func newDeletion(entity string, id int) *deletion {
+	return &deletion{
+		entity: entity,
+		id:     id,
+		now:    time.Now(),
+		books:  make(map[int]bool),
+		result: domain.DeleteResult{
+			Deleted: make([]domain.Reference, 0),
+		},
+	}
+}
+
+func (d *deletion) delete(reference domain.Reference, change func()) {
+	d.result.Deleted = append(d.result.Deleted, reference)
+	d.changes = append(d.changes, change)
+}
+
+func (d *deletion) archive(reference domain.Reference, change func()) {
+	d.result.Archived = append(d.result.Archived, reference)
+	d.changes = append(d.changes, change)
+}
+
+func (d *deletion) cancel(reference domain.Reference, change func()) {
+	d.result.Cancelled = append(d.result.Cancelled, reference)
+	d.changes = append(d.changes, change)
+}
+
+// commit applies the collected changes, or returns a *domain.ConflictError
+// listing every blocking reference without applying any of them.
+func (d *deletion) commit() (*domain.DeleteResult, error) {
+	if len(d.conflicts) > 0 {
+		return nil, &domain.ConflictError{
+			Entity:     d.entity,
+			ID:         d.id,
+			References: d.conflicts,
+		}
+	}
+
+	for _, change := range d.changes {
+		change()
+	}
+
+	return &d.result, nil
+}
+
+// relationReferences are the records referring to an entity through one
+// relation.
+type relationReferences struct {
+	relation   string
+	references []domain.Reference
+}
+
+// resolve applies the rules of the relations of an entity. It reports
+// whether the entity has to be archived, which is the case when any relation
+// with references archives: the entity then stays and nothing else is
+// touched. Otherwise references through restricting relations become
+// conflicts and the caller cascades the rest. The caller must hold r.mu.
+func (r *Repository) resolve(d *deletion, relations []relationReferences) bool {
+	for _, relation := range relations {
+		if len(relation.references) > 0 && r.deleteRules[relation.relation] == domain.DeleteArchive {
+			return true
+		}
+	}
+
+	for _, relation := range relations {
+		if r.deleteRules[relation.relation] == domain.DeleteRestrict {
+			d.conflicts = append(d.conflicts, relation.references...)
+		}
+	}
+
+	return false
+}
+
+// references turns ids into references of an entity through a relation.
+func references(relation, entity string, ids []int) []domain.Reference {
+	sort.Ints(ids)
+
+	refs := make([]domain.Reference, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, domain.Reference{
+			Relation: relation,
+			Entity:   entity,
+			ID:       id,
+		})
+	}
+
+	return refs
+}
+
+// planBookDeletion adds the book to the deletion. Cascading to instances
+// deletes only copies on the shelf: a copy that is on loan, on the hold shelf
+// or being transferred still blocks. It reports whether the book is archived
+// rather than deleted. The caller must hold r.mu.
+func (r *Repository) planBookDeletion(d *deletion, bookID int, relation string) bool {
+	if archived, ok := d.books[bookID]; ok {
+		return archived
+	}
+	d.books[bookID] = false
+
+	instanceIDs := make([]int, 0)
+	for instanceID, instance := range r.instance {
+		if instance.BookID == bookID {
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+	}
+
+	holdIDs := make([]int, 0)
+	for holdID, hold := range r.hold {
+		if hold.BookID == bookID && hold.IsActive() {
+			holdIDs = append(holdIDs, holdID)
+		}
+	}
+
+	orderIDs := make([]int, 0)
+	for orderID, order := range r.order {
+		if order.Status == domain.OrderReceived || order.Status == domain.OrderCancelled {
+			continue
+		}
+
+		for _, line := range order.Lines {
+			if line.BookID == bookID {
+				orderIDs = append(orderIDs, orderID)
+				break
+			}
+		}
+	}
+
+	instances := references(domain.RelationBookInstances, "instance", instanceIDs)
+	holds := references(domain.RelationBookHolds, "hold", holdIDs)
+	reference := domain.Reference{Relation: relation, Entity: "book", ID: bookID}
+
+	if r.resolve(d, []relationReferences{
+		{domain.RelationBookInstances, instances},
+		{domain.RelationBookHolds, holds},
+		{domain.RelationBookOrders, references(domain.RelationBookOrders, "order", orderIDs)},
+	}) {
+		d.archive(reference, func() {
+			book := r.books[bookID]
+			book.Archived = true
+			r.putBook(bookID, book)
+		})
+		d.books[bookID] = true
+		return true
+	}
+
+	if r.deleteRules[domain.RelationBookInstances] == domain.DeleteCascade {
+		transfers := make(map[int]int)
+		for transferID, transfer := range r.transfer {
+			if transfer.Status == domain.TransferRequested || transfer.Status == domain.TransferInTransit {
+				transfers[transfer.InstanceID] = transferID
+			}
+		}
+
+		for _, instance := range instances {
+			instanceID := instance.ID
+			status := r.instance[instanceID].Status
+			switch {
+			case status == domain.InstanceOnLoan || status == domain.InstanceOnHoldShelf || status == domain.InstanceInTransit:
+				instance.Reason = fmt.Sprintf("instance is %s", status)
+				d.conflicts = append(d.conflicts, instance)
+			case transfers[instanceID] != 0:
+				instance.Reason = fmt.Sprintf("instance is requested by transfer %d", transfers[instanceID])
+				d.conflicts = append(d.conflicts, instance)
+			default:
+				d.delete(instance, func() {
+					remove(r, r.instance, instanceID)
+				})
+			}
+		}
+	}
+
+	if r.deleteRules[domain.RelationBookHolds] == domain.DeleteCascade {
+		for _, hold := range holds {
+			holdID := hold.ID
+			d.cancel(hold, func() {
+				hold := r.hold[holdID]
+				hold.Status = domain.HoldCancelled
+				hold.CloseDate = &d.now
+				put(r, r.hold, holdID, hold)
+			})
+		}
+	}
+
+	d.delete(reference, func() {
+		if book := r.books[bookID]; book.ISBN != "" {
+			remove(r, r.isbn, book.ISBN)
+		}
+
+		remove(r, r.books, bookID)
+		r.indexBook(bookID)
+	})
+
+	return false
+}
+
+// planAuthorDeletion adds the author to the deletion. Cascading deletes every
+// book crediting the author in any role, with the rules of the books applied
+// in turn. A book archived by its own rules keeps the author and blocks. The
+// caller must hold r.mu.
+func (r *Repository) planAuthorDeletion(d *deletion, authorID int) {
+	books := references(domain.RelationAuthorBooks, "book", r.authorBooks(authorID))
+	reference := domain.Reference{Entity: "author", ID: authorID}
+
+	if r.resolve(d, []relationReferences{{domain.RelationAuthorBooks, books}}) {
+		d.archive(reference, func() {
+			author := r.author[authorID]
+			author.Archived = true
+			put(r, r.author, authorID, author)
+		})
+		return
+	}
+
+	if r.deleteRules[domain.RelationAuthorBooks] == domain.DeleteCascade {
+		for _, book := range books {
+			if r.planBookDeletion(d, book.ID, domain.RelationAuthorBooks) {
+				book.Reason = "book is archived instead of deleted"
+				d.conflicts = append(d.conflicts, book)
+			}
+		}
+	}
+
+	d.delete(reference, func() {
+		remove(r, r.author, authorID)
+	})
+}
+
+// planDeletion adds an entity without cascading relations to the deletion:
+// it is removed when nothing refers to it and archived or blocked otherwise.
+// The caller must hold r.mu.
+func (r *Repository) planDeletion(d *deletion, relations []relationReferences, archive, remove func()) {
+	reference := domain.Reference{Entity: d.entity, ID: d.id}
+	if r.resolve(d, relations) {
+		d.archive(reference, archive)
+		return
+	}
+
+	d.delete(reference, remove)
+}
@@ -0,0 +1,418 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+)
+
+func (r *Repository) GetGenre(genreID int) (*domain.GenreEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	genre, ok := r.genres[genreID]
+	if !ok {
+		return nil, fmt.Errorf("genre not found")
+	}
+
+	return &domain.GenreEntry{
+		GenreID:       genreID,
+		GenreMapField: genre,
+	}, nil
+}
+
+func (r *Repository) CreateGenre(actor domain.Actor, genre domain.GenreMapField) (*domain.GenreEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	if genre.Name == "" {
+		return nil, fmt.Errorf("genre name is required")
+	}
+
+	r.genreSeq++
+	genreID := r.genreSeq
+	put(r, r.genres, genreID, genre)
+
+	err := r.record(actor, "create_genre", "genre", genreID, nil, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.GenreEntry{
+		GenreID:       genreID,
+		GenreMapField: genre,
+	}, nil
+}
+
+func (r *Repository) UpdateGenre(actor domain.Actor, genreID int, genre domain.GenreMapField) (*domain.GenreEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.genres[genreID]
+	if !ok {
+		return nil, fmt.Errorf("genre not found")
+	}
+
+	if genre.Name == "" {
+		return nil, fmt.Errorf("genre name is required")
+	}
+
+	genre.Archived = previous.Archived
+	put(r, r.genres, genreID, genre)
+
+	err := r.record(actor, "update_genre", "genre", genreID, previous, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.GenreEntry{
+		GenreID:       genreID,
+		GenreMapField: genre,
+	}, nil
+}
+
+// DeleteGenre deletes the genre or archives it, following the delete rule of
+// the books having it.
+func (r *Repository) DeleteGenre(actor domain.Actor, genreID int) (*domain.DeleteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.genres[genreID]
+	if !ok {
+		return nil, fmt.Errorf("genre not found")
+	}
+
+	bookIDs := make([]int, 0)
+	for bookID, book := range r.books {
+		for _, id := range book.GenreIDs {
+			if id == genreID {
+				bookIDs = append(bookIDs, bookID)
+				break
+			}
+		}
+	}
+
+	d := newDeletion("genre", genreID)
+	r.planDeletion(d, []relationReferences{
+		{domain.RelationGenreBooks, references(domain.RelationGenreBooks, "book", bookIDs)},
+	}, func() {
+		genre := r.genres[genreID]
+		genre.Archived = true
+		put(r, r.genres, genreID, genre)
+	}, func() {
+		remove(r, r.genres, genreID)
+	})
+
+	result, err := d.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.record(actor, "delete_genre", "genre", genreID, previous, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetLanguage(languageID int) (*domain.LanguageEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	language, ok := r.language[languageID]
+	if !ok {
+		return nil, fmt.Errorf("language not found")
+	}
+
+	return &domain.LanguageEntry{
+		LanguageID:       languageID,
+		LanguageMapField: language,
+	}, nil
+}
+
+func (r *Repository) CreateLanguage(actor domain.Actor, language domain.LanguageMapField) (*domain.LanguageEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	if language.Name == "" {
+		return nil, fmt.Errorf("language name is required")
+	}
+
+	r.languageSeq++
+	languageID := r.languageSeq
+	put(r, r.language, languageID, language)
+
+	err := r.record(actor, "create_language", "language", languageID, nil, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LanguageEntry{
+		LanguageID:       languageID,
+		LanguageMapField: language,
+	}, nil
+}
+
+func (r *Repository) UpdateLanguage(actor domain.Actor, languageID int, language domain.LanguageMapField) (*domain.LanguageEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.language[languageID]
+	if !ok {
+		return nil, fmt.Errorf("language not found")
+	}
+
+	if language.Name == "" {
+		return nil, fmt.Errorf("language name is required")
+	}
+
+	language.Archived = previous.Archived
+	put(r, r.language, languageID, language)
+
+	err := r.record(actor, "update_language", "language", languageID, previous, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LanguageEntry{
+		LanguageID:       languageID,
+		LanguageMapField: language,
+	}, nil
+}
+
+// DeleteLanguage deletes the language or archives it, following the delete
+// rule of the books written in it.
+func (r *Repository) DeleteLanguage(actor domain.Actor, languageID int) (*domain.DeleteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.language[languageID]
+	if !ok {
+		return nil, fmt.Errorf("language not found")
+	}
+
+	bookIDs := make([]int, 0)
+	for bookID, book := range r.books {
+		if book.LanguageID == languageID {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	d := newDeletion("language", languageID)
+	r.planDeletion(d, []relationReferences{
+		{domain.RelationLanguageBooks, references(domain.RelationLanguageBooks, "book", bookIDs)},
+	}, func() {
+		language := r.language[languageID]
+		language.Archived = true
+		put(r, r.language, languageID, language)
+	}, func() {
+		remove(r, r.language, languageID)
+	})
+
+	result, err := d.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.record(actor, "delete_language", "language", languageID, previous, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetProduction(productionID int) (*domain.ProductionEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	production, ok := r.production[productionID]
+	if !ok {
+		return nil, fmt.Errorf("production not found")
+	}
+
+	return &domain.ProductionEntry{
+		ProductionID:       productionID,
+		ProductionMapField: production,
+	}, nil
+}
+
+func (r *Repository) CreateProduction(actor domain.Actor, production domain.ProductionMapField) (*domain.ProductionEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	if production.Name == "" {
+		return nil, fmt.Errorf("production name is required")
+	}
+
+	r.productionSeq++
+	productionID := r.productionSeq
+	put(r, r.production, productionID, production)
+
+	err := r.record(actor, "create_production", "production", productionID, nil, production)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ProductionEntry{
+		ProductionID:       productionID,
+		ProductionMapField: production,
+	}, nil
+}
+
+func (r *Repository) UpdateProduction(actor domain.Actor, productionID int, production domain.ProductionMapField) (*domain.ProductionEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.production[productionID]
+	if !ok {
+		return nil, fmt.Errorf("production not found")
+	}
+
+	if production.Name == "" {
+		return nil, fmt.Errorf("production name is required")
+	}
+
+	production.Archived = previous.Archived
+	put(r, r.production, productionID, production)
+
+	err := r.record(actor, "update_production", "production", productionID, previous, production)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ProductionEntry{
+		ProductionID:       productionID,
+		ProductionMapField: production,
+	}, nil
+}
+
+// DeleteProduction deletes the production or archives it, following the
+// delete rules of its books, authors and purchase orders.
+func (r *Repository) DeleteProduction(actor domain.Actor, productionID int) (*domain.DeleteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	previous, ok := r.production[productionID]
+	if !ok {
+		return nil, fmt.Errorf("production not found")
+	}
+
+	bookIDs := make([]int, 0)
+	for bookID, book := range r.books {
+		if book.ProductionID == productionID {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	authorIDs := make([]int, 0)
+	for authorID, author := range r.author {
+		if author.ProductionID == productionID {
+			authorIDs = append(authorIDs, authorID)
+		}
+	}
+
+	orderIDs := make([]int, 0)
+	for orderID, order := range r.order {
+		if order.ProductionID == productionID {
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+
+	d := newDeletion("production", productionID)
+	r.planDeletion(d, []relationReferences{
+		{domain.RelationProductionBooks, references(domain.RelationProductionBooks, "book", bookIDs)},
+		{domain.RelationProductionAuthors, references(domain.RelationProductionAuthors, "author", authorIDs)},
+		{domain.RelationProductionOrders, references(domain.RelationProductionOrders, "order", orderIDs)},
+	}, func() {
+		production := r.production[productionID]
+		production.Archived = true
+		put(r, r.production, productionID, production)
+	}, func() {
+		remove(r, r.production, productionID)
+	})
+
+	result, err := d.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.record(actor, "delete_production", "production", productionID, previous, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (r *Repository) GetFines(readerID int) (*domain.FineAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	account := r.fineAccount(readerID, time.Now())
+	return &account, nil
+}
+
+func (r *Repository) PayFine(actor domain.Actor, readerID, amount int) (*domain.FineAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	_, ok = r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid amount")
+	}
+
+	now := time.Now()
+	account := r.fineAccount(readerID, now)
+	if amount > account.Balance {
+		return nil, fmt.Errorf("amount exceeds outstanding balance %d", account.Balance)
+	}
+
+	r.addFine(domain.FineMapField{
+		ReaderID: readerID,
+		Kind:     domain.FinePayment,
+		Amount:   amount,
+		AdminID:  actor.UserID,
+		Date:     now,
+	})
+
+	previous := account
+	account = r.fineAccount(readerID, now)
+
+	err := r.record(actor, "pay_fine", "reader", readerID, previous, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *Repository) WaiveFine(actor domain.Actor, readerID, amount int, reason string) (*domain.FineAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	_, ok = r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid amount")
+	}
+
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	now := time.Now()
+	account := r.fineAccount(readerID, now)
+	if amount > account.Balance {
+		return nil, fmt.Errorf("amount exceeds outstanding balance %d", account.Balance)
+	}
+
+	r.addFine(domain.FineMapField{
+		ReaderID: readerID,
+		Kind:     domain.FineWaiver,
+		Amount:   amount,
+		AdminID:  actor.UserID,
+		Reason:   reason,
+		Date:     now,
+	})
+
+	previous := account
+	account = r.fineAccount(readerID, now)
+
+	err := r.record(actor, "waive_fine", "reader", readerID, previous, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// chargeOverdueFine posts the fine of a returned loan to the ledger. The
+// caller must hold r.mu.
+func (r *Repository) chargeOverdueFine(loan domain.LoanMapField) {
+	fine := loan.Fine(*loan.ReturnDate)
+	if fine == 0 {
+		return
+	}
+
+	r.addFine(domain.FineMapField{
+		ReaderID: loan.ReaderID,
+		Kind:     domain.FineCharge,
+		Amount:   fine,
+		LoanID:   loan.LoanID,
+		Reason:   "overdue",
+		Date:     *loan.ReturnDate,
+	})
+}
+
+// addFine appends an entry to the ledger. The caller must hold r.mu.
+func (r *Repository) addFine(fine domain.FineMapField) {
+	r.fineSeq++
+	fine.FineID = r.fineSeq
+	put(r, r.fine, fine.FineID, fine)
+}
+
+// fineAccount sums up the ledger of the reader together with the fines still
+// accruing on overdue loans. The caller must hold r.mu.
+func (r *Repository) fineAccount(readerID int, now time.Time) domain.FineAccount {
+	account := domain.FineAccount{
+		ReaderID: readerID,
+		Ledger:   make([]domain.FineMapField, 0),
+	}
+
+	for _, fine := range r.fine {
+		if fine.ReaderID != readerID {
+			continue
+		}
+
+		switch fine.Kind {
+		case domain.FineCharge:
+			account.Charged += fine.Amount
+		case domain.FinePayment:
+			account.Paid += fine.Amount
+		case domain.FineWaiver:
+			account.Waived += fine.Amount
+		}
+
+		account.Ledger = append(account.Ledger, fine)
+	}
+
+	sort.Slice(account.Ledger, func(i, j int) bool {
+		return account.Ledger[i].FineID < account.Ledger[j].FineID
+	})
+
+	for _, loan := range r.loan {
+		if loan.ReaderID == readerID && loan.IsOpen() {
+			account.Accruing += loan.Fine(now)
+		}
+	}
+
+	account.Balance = account.Charged + account.Accruing - account.Paid - account.Waived
+
+	return account
+}
@@ -0,0 +1,68 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPayFine(t *testing.T) {
+	reader := domain.Actor{
+		Principal: domain.Principal{UserID: testReaderID, Roles: []string{domain.RoleReader}},
+		RequestID: "test",
+	}
+
+	tests := []struct {
+		name    string
+		actor   domain.Actor
+		amount  int
+		wantErr string
+		paid    int
+	}{
+		{name: "admin takes a payment", actor: testAdmin, amount: 20, paid: 20},
+		{name: "admin takes the whole balance", actor: testAdmin, amount: 50, paid: 50},
+		{name: "reader cannot record a payment", actor: reader, amount: 20, wantErr: "permission denied"},
+		{name: "payment above the balance", actor: testAdmin, amount: 60, wantErr: "exceeds outstanding balance"},
+		{name: "non-positive payment", actor: testAdmin, amount: 0, wantErr: "invalid amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t, WithAuditLog(filepath.Join(t.TempDir(), "audit.jsonl")))
+			r.addFine(domain.FineMapField{ReaderID: testReaderID, Kind: domain.FineCharge, Amount: 50})
+
+			account, err := r.PayFine(tt.actor, testReaderID, tt.amount)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PayFine = %v, want %q", err, tt.wantErr)
+				}
+
+				if entries := r.audit.Query(audit.Filter{Entity: "reader"}); len(entries) != 0 {
+					t.Errorf("audit log = %v, want no entries", entries)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("PayFine: %v", err)
+			}
+
+			if account.Paid != tt.paid || account.Balance != 50-tt.paid {
+				t.Errorf("account paid %d, balance %d, want %d and %d", account.Paid, account.Balance, tt.paid, 50-tt.paid)
+			}
+
+			payment := account.Ledger[len(account.Ledger)-1]
+			if payment.Kind != domain.FinePayment || payment.AdminID != testAdminID {
+				t.Errorf("last ledger entry = %+v, want a payment taken by admin %d", payment, testAdminID)
+			}
+
+			entries := r.audit.Query(audit.Filter{Entity: "reader", EntityID: testReaderID})
+			if len(entries) != 1 || entries[0].Action != "pay_fine" || entries[0].Actor != testAdminID {
+				t.Errorf("audit log = %+v, want one pay_fine entry by admin %d", entries, testAdminID)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (r *Repository) PlaceHold(readerID, bookID int) (*domain.HoldMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	err := r.checkReader(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	book, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	if book.Archived {
+		return nil, fmt.Errorf("book is archived")
+	}
+
+	for _, hold := range r.hold {
+		if hold.ReaderID == readerID && hold.BookID == bookID && hold.IsActive() {
+			return nil, fmt.Errorf("reader already has a hold on the book")
+		}
+	}
+
+	for _, loan := range r.loan {
+		if loan.ReaderID == readerID && loan.IsOpen() && r.instance[loan.InstanceID].BookID == bookID {
+			return nil, fmt.Errorf("reader already has the book on loan")
+		}
+	}
+
+	for instanceID, instance := range r.instance {
+		if instance.BookID != bookID || instance.Status != domain.InstanceAvailable {
+			continue
+		}
+
+		if _, ok = r.requestedTransferByInstance(instanceID); !ok {
+			return nil, fmt.Errorf("book has available instances")
+		}
+	}
+
+	r.holdSeq++
+	hold := domain.HoldMapField{
+		HoldID:     r.holdSeq,
+		ReaderID:   readerID,
+		BookID:     bookID,
+		Status:     domain.HoldWaiting,
+		PlacedDate: now,
+	}
+	put(r, r.hold, hold.HoldID, hold)
+
+	return &hold, nil
+}
+
+func (r *Repository) CancelHold(readerID, holdID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	hold, ok := r.hold[holdID]
+	if !ok || hold.ReaderID != readerID {
+		return fmt.Errorf("hold not found")
+	}
+
+	if !hold.IsActive() {
+		return fmt.Errorf("hold is already %s", hold.Status)
+	}
+
+	wasReady := hold.Status == domain.HoldReady
+	hold.Status = domain.HoldCancelled
+	hold.CloseDate = &now
+	put(r, r.hold, holdID, hold)
+
+	if wasReady {
+		return r.shelveInstance(hold.InstanceID, now)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetReaderHolds(readerID int) ([]domain.HoldMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	holds := make([]domain.HoldMapField, 0)
+	for _, hold := range r.sortedHolds() {
+		if hold.ReaderID == readerID {
+			holds = append(holds, hold)
+		}
+	}
+
+	return holds, nil
+}
+
+// GetBookHolds returns the active holds on the book in queue order: holds
+// waiting on the hold shelf first, then the waiting ones first come first served.
+func (r *Repository) GetBookHolds(bookID int) ([]domain.HoldMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.books[bookID]
+	if !ok {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	holds := make([]domain.HoldMapField, 0)
+	for _, hold := range r.sortedHolds() {
+		if hold.BookID == bookID && hold.Status == domain.HoldReady {
+			holds = append(holds, hold)
+		}
+	}
+
+	for _, hold := range r.sortedHolds() {
+		if hold.BookID == bookID && hold.Status == domain.HoldWaiting {
+			holds = append(holds, hold)
+		}
+	}
+
+	return holds, nil
+}
+
+// shelveInstance puts a copy that came back to the library either on the hold
+// shelf for the first reader in the queue of its book or back on the shelf,
+// from where a copy away from its home branch is sent home. The caller must
+// hold r.mu.
+func (r *Repository) shelveInstance(instanceID int, now time.Time) error {
+	instance, ok := r.instance[instanceID]
+	if !ok {
+		return fmt.Errorf("instance not found")
+	}
+
+	for _, hold := range r.sortedHolds() {
+		if hold.BookID != instance.BookID || hold.Status != domain.HoldWaiting {
+			continue
+		}
+
+		err := r.setInstanceStatus(instanceID, domain.InstanceOnHoldShelf)
+		if err != nil {
+			return err
+		}
+
+		readyDate := now
+		expireDate := now.Add(r.holdPickupWindow)
+		hold.Status = domain.HoldReady
+		hold.InstanceID = instanceID
+		hold.ReadyDate = &readyDate
+		hold.ExpireDate = &expireDate
+		put(r, r.hold, hold.HoldID, hold)
+
+		return nil
+	}
+
+	err := r.setInstanceStatus(instanceID, domain.InstanceAvailable)
+	if err != nil {
+		return err
+	}
+
+	r.returnHome(instanceID, now)
+
+	return nil
+}
+
+// expireHolds closes the holds whose pickup window is over and passes their
+// copies on to the next reader in line. The caller must hold r.mu.
+func (r *Repository) expireHolds(now time.Time) {
+	for _, hold := range r.sortedHolds() {
+		if hold.Status != domain.HoldReady || hold.ExpireDate == nil || hold.ExpireDate.After(now) {
+			continue
+		}
+
+		closeDate := now
+		hold.Status = domain.HoldExpired
+		hold.CloseDate = &closeDate
+		put(r, r.hold, hold.HoldID, hold)
+
+		// a copy can always leave the hold shelf for the shelf or the next hold
+		_ = r.shelveInstance(hold.InstanceID, now)
+	}
+}
+
+// firstWaitingHold returns the id of the hold at the head of the queue of the
+// book. The caller must hold r.mu.
+func (r *Repository) firstWaitingHold(bookID int) (int, bool) {
+	for _, hold := range r.sortedHolds() {
+		if hold.BookID == bookID && hold.Status == domain.HoldWaiting {
+			return hold.HoldID, true
+		}
+	}
+
+	return 0, false
+}
+
+// readyHoldByInstance returns the id of the hold the instance is kept on the
+// hold shelf for. The caller must hold r.mu.
+func (r *Repository) readyHoldByInstance(instanceID int) (int, bool) {
+	for holdID, hold := range r.hold {
+		if hold.InstanceID == instanceID && hold.Status == domain.HoldReady {
+			return holdID, true
+		}
+	}
+
+	return 0, false
+}
+
+// sortedHolds returns every hold ordered by id, which is also the order they
+// were placed in. The caller must hold r.mu.
+func (r *Repository) sortedHolds() []domain.HoldMapField {
+	holds := make([]domain.HoldMapField, 0, len(r.hold))
+	for _, hold := range r.hold {
+		holds = append(holds, hold)
+	}
+
+	sort.Slice(holds, func(i, j int) bool {
+		return holds[i].HoldID < holds[j].HoldID
+	})
+
+	return holds
+}
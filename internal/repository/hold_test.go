@@ -0,0 +1,170 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestPlaceHold(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, r *Repository)
+		wantErr bool
+	}{
+		{
+			name: "every copy on loan to another reader",
+			prepare: func(t *testing.T, r *Repository) {
+				lend(t, r, testOtherID, time.Now().Add(time.Hour))
+			},
+		},
+		{
+			name: "copy on the shelf",
+			prepare: func(t *testing.T, r *Repository) {
+				addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			},
+			wantErr: true,
+		},
+		{
+			name: "only copy on loan to the reader",
+			prepare: func(t *testing.T, r *Repository) {
+				lend(t, r, testReaderID, time.Now().Add(time.Hour))
+			},
+			wantErr: true,
+		},
+		{
+			name: "hold already placed",
+			prepare: func(t *testing.T, r *Repository) {
+				lend(t, r, testOtherID, time.Now().Add(time.Hour))
+				if _, err := r.PlaceHold(testReaderID, testBookID); err != nil {
+					t.Fatalf("PlaceHold: %v", err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t)
+			tt.prepare(t, r)
+
+			_, err := r.PlaceHold(testReaderID, testBookID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlaceHold = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHoldQueue(t *testing.T) {
+	r := newTestRepository(t)
+	instanceID, _ := lend(t, r, testThirdID, time.Now().Add(time.Hour))
+
+	first, err := r.PlaceHold(testReaderID, testBookID)
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+
+	second, err := r.PlaceHold(testOtherID, testBookID)
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+
+	if err = r.ReturnBook(testThirdID, instanceID); err != nil {
+		t.Fatalf("ReturnBook: %v", err)
+	}
+
+	if hold := r.hold[first.HoldID]; hold.Status != domain.HoldReady || hold.InstanceID != instanceID {
+		t.Fatalf("first hold = %+v, want it ready with copy %d", hold, instanceID)
+	}
+
+	if _, err = r.TakeBook(testOtherID, instanceID, testTerms); err == nil {
+		t.Error("second in line took the copy kept for the first")
+	}
+
+	// the first reader does not come in time
+	expired := time.Now().Add(-time.Minute)
+	hold := r.hold[first.HoldID]
+	hold.ExpireDate = &expired
+	r.hold[first.HoldID] = hold
+	r.expireHolds(time.Now())
+
+	if hold = r.hold[first.HoldID]; hold.Status != domain.HoldExpired {
+		t.Errorf("first hold is %s, want %s", hold.Status, domain.HoldExpired)
+	}
+
+	if hold = r.hold[second.HoldID]; hold.Status != domain.HoldReady || hold.InstanceID != instanceID {
+		t.Fatalf("second hold = %+v, want it ready with copy %d", hold, instanceID)
+	}
+
+	if err = r.CancelHold(testOtherID, second.HoldID); err != nil {
+		t.Fatalf("CancelHold: %v", err)
+	}
+
+	if status := r.instance[instanceID].Status; status != domain.InstanceAvailable {
+		t.Errorf("copy is %s once the queue is empty, want %s", status, domain.InstanceAvailable)
+	}
+}
+
+func TestCopyBackInCirculationServesHolds(t *testing.T) {
+	for _, status := range []domain.InstanceStatus{domain.InstanceInRepair, domain.InstanceLost, domain.InstanceWithdrawn} {
+		t.Run(string(status), func(t *testing.T) {
+			r := newTestRepository(t)
+			instanceID := addInstance(r, status, testHomeBranchID)
+			hold, err := r.PlaceHold(testReaderID, testBookID)
+			if err != nil {
+				t.Fatalf("PlaceHold: %v", err)
+			}
+
+			if err = r.UpdateInstanceStatus(testAdmin, instanceID, domain.InstanceAvailable); err != nil {
+				t.Fatalf("UpdateInstanceStatus: %v", err)
+			}
+
+			if got := r.instance[instanceID].Status; got != domain.InstanceOnHoldShelf {
+				t.Errorf("copy is %s, want %s", got, domain.InstanceOnHoldShelf)
+			}
+
+			if got := r.hold[hold.HoldID]; got.Status != domain.HoldReady || got.InstanceID != instanceID {
+				t.Errorf("hold = %+v, want it ready with copy %d", got, instanceID)
+			}
+
+			if _, err = r.TakeBook(testOtherID, instanceID, testTerms); err == nil {
+				t.Error("another reader took the copy ahead of the queue")
+			}
+		})
+	}
+}
+
+func TestCheckoutFromShelfKeepsQueue(t *testing.T) {
+	r := newTestRepository(t)
+
+	// a hold placed while the only copy on the shelf was reserved for a
+	// transfer, which was then withdrawn
+	instanceID := addInstance(r, domain.InstanceAvailable, testOtherBranchID)
+	transfer, err := r.RequestTransfer(testOtherID, testBookID, testHomeBranchID)
+	if err != nil {
+		t.Fatalf("RequestTransfer: %v", err)
+	}
+
+	hold, err := r.PlaceHold(testReaderID, testBookID)
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+
+	if err = r.CancelTransfer(transfer.TransferID, testOtherID); err != nil {
+		t.Fatalf("CancelTransfer: %v", err)
+	}
+
+	if _, err = r.TakeBook(testOtherID, instanceID, testTerms); err == nil {
+		t.Fatal("a reader took the copy ahead of the queue")
+	}
+
+	if _, err = r.TakeBook(testReaderID, instanceID, testTerms); err != nil {
+		t.Fatalf("TakeBook by the first in line: %v", err)
+	}
+
+	if got := r.hold[hold.HoldID]; got.Status != domain.HoldFulfilled || got.InstanceID != instanceID {
+		t.Errorf("hold = %+v, want it fulfilled with copy %d", got, instanceID)
+	}
+}
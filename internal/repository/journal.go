@@ -0,0 +1,103 @@
+package book_inventory_system_repository
+
+// journal keeps what is needed to undo a privileged operation in progress:
+// the previous values of the records it changed, in the order it changed
+// them, and of the id counters. Only the touched records are kept, so its
+// cost grows with the change rather than with the repository.
+type journal struct {
+	undo    []func()
+	seqs    [14]int
+	reindex map[int]struct{}
+}
+
+// begin starts journaling the changes of a privileged operation. The
+// operation defers rollback right after, so that the changes are undone when
+// it fails on the way, and record ends the journal once the audit entry is
+// written. The caller must hold r.mu.
+func (r *Repository) begin() {
+	j := &journal{
+		undo:    make([]func(), 0),
+		reindex: make(map[int]struct{}),
+	}
+
+	for i, seq := range r.seqs() {
+		j.seqs[i] = *seq
+	}
+
+	r.journal = j
+}
+
+// rollback undoes every change journaled since begin, newest first, and
+// refreshes the search index of the books they touched. It does nothing once
+// the journal is ended. The caller must hold r.mu.
+func (r *Repository) rollback() {
+	j := r.journal
+	if j == nil {
+		return
+	}
+
+	r.journal = nil
+
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		j.undo[i]()
+	}
+
+	for i, seq := range r.seqs() {
+		*seq = j.seqs[i]
+	}
+
+	for bookID := range j.reindex {
+		r.indexBook(bookID)
+	}
+}
+
+// seqs lists the id counters a journal keeps.
+func (r *Repository) seqs() [14]*int {
+	return [14]*int{
+		&r.loanSeq,
+		&r.holdSeq,
+		&r.fineSeq,
+		&r.stocktakeSeq,
+		&r.transferSeq,
+		&r.orderSeq,
+		&r.banSeq,
+		&r.sessionSeq,
+		&r.bookSeq,
+		&r.authorSeq,
+		&r.genreSeq,
+		&r.languageSeq,
+		&r.productionSeq,
+		&r.instanceSeq,
+	}
+}
+
+// put stores the value under the key of one of the maps of r. Every change
+// made after the dumps are loaded goes through put or remove, so that the
+// journal sees it. The caller must hold r.mu.
+func put[K comparable, V any](r *Repository, m map[K]V, key K, value V) {
+	remember(r, m, key)
+	m[key] = value
+}
+
+// remove deletes the key from one of the maps of r. The caller must hold r.mu.
+func remove[K comparable, V any](r *Repository, m map[K]V, key K) {
+	remember(r, m, key)
+	delete(m, key)
+}
+
+// remember journals the value the key has now, or that it has none. The
+// caller must hold r.mu.
+func remember[K comparable, V any](r *Repository, m map[K]V, key K) {
+	if r.journal == nil {
+		return
+	}
+
+	previous, ok := m[key]
+	r.journal.undo = append(r.journal.undo, func() {
+		if ok {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testState copies the records the operations under test change.
+type testState struct {
+	books    map[int]domain.BookMapField
+	isbn     map[string]int
+	instance map[int]domain.InstanceMapField
+	reader   map[int]domain.ReaderMapField
+	loan     map[int]domain.LoanMapField
+	fine     map[int]domain.FineMapField
+	hold     map[int]domain.HoldMapField
+	transfer map[int]domain.TransferMapField
+	seqs     [14]int
+}
+
+func stateOf(r *Repository) testState {
+	s := testState{
+		books:    copyTestMap(r.books),
+		isbn:     copyTestMap(r.isbn),
+		instance: copyTestMap(r.instance),
+		reader:   copyTestMap(r.reader),
+		loan:     copyTestMap(r.loan),
+		fine:     copyTestMap(r.fine),
+		hold:     copyTestMap(r.hold),
+		transfer: copyTestMap(r.transfer),
+	}
+
+	for i, seq := range r.seqs() {
+		s.seqs[i] = *seq
+	}
+
+	return s
+}
+
+func copyTestMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+func TestRollbackOnAuditFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, r *Repository)
+		change  func(r *Repository) error
+	}{
+		{
+			name: "create book",
+			change: func(r *Repository) error {
+				book := newTestBook("Anna Karenina")
+				book.ISBN = "9780306406157"
+				_, err := r.CreateBook(testAdmin, book)
+				return err
+			},
+		},
+		{
+			name: "declare a copy on loan lost",
+			prepare: func(t *testing.T, r *Repository) {
+				lend(t, r, testReaderID, time.Now().Add(-48*time.Hour))
+			},
+			change: func(r *Repository) error {
+				return r.UpdateInstanceStatus(testAdmin, 1, domain.InstanceLost)
+			},
+		},
+		{
+			name: "waive a fine",
+			prepare: func(t *testing.T, r *Repository) {
+				r.addFine(domain.FineMapField{ReaderID: testReaderID, Kind: domain.FineCharge, Amount: 50})
+			},
+			change: func(r *Repository) error {
+				_, err := r.WaiveFine(testAdmin, testReaderID, 20, "first offence")
+				return err
+			},
+		},
+		{
+			name: "pay a fine",
+			prepare: func(t *testing.T, r *Repository) {
+				r.addFine(domain.FineMapField{ReaderID: testReaderID, Kind: domain.FineCharge, Amount: 50})
+			},
+			change: func(r *Repository) error {
+				_, err := r.PayFine(testAdmin, testReaderID, 20)
+				return err
+			},
+		},
+		{
+			name: "receive a transfer home",
+			prepare: func(t *testing.T, r *Repository) {
+				instanceID := addInstance(r, domain.InstanceInTransit, testOtherBranchID)
+				r.transferSeq++
+				r.transfer[r.transferSeq] = domain.TransferMapField{
+					TransferID:   r.transferSeq,
+					InstanceID:   instanceID,
+					BookID:       testBookID,
+					FromBranchID: testOtherBranchID,
+					ToBranchID:   testHomeBranchID,
+					Status:       domain.TransferInTransit,
+				}
+			},
+			change: func(r *Repository) error {
+				_, err := r.ReceiveTransfer(testAdmin, 1)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t, WithAuditLog(filepath.Join(t.TempDir(), "audit.jsonl")))
+			if tt.prepare != nil {
+				tt.prepare(t, r)
+			}
+
+			before := stateOf(r)
+			r.audit.Close()
+
+			err := tt.change(r)
+			if err == nil || !strings.Contains(err.Error(), "audit log") {
+				t.Fatalf("change = %v, want an audit log error", err)
+			}
+
+			if after := stateOf(r); !reflect.DeepEqual(after, before) {
+				t.Errorf("state after the failed change = %+v, want %+v", after, before)
+			}
+
+			if r.journal != nil {
+				t.Error("journal is left open after the rollback")
+			}
+
+			if results := r.search.Search("anna karenina"); len(results) != 0 {
+				t.Errorf("search index keeps the rolled back book: %v", results)
+			}
+		})
+	}
+}
+
+func TestRecordKeepsChange(t *testing.T) {
+	r := newTestRepository(t, WithAuditLog(filepath.Join(t.TempDir(), "audit.jsonl")))
+
+	entry, err := r.CreateBook(testAdmin, newTestBook("Anna Karenina"))
+	if err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+
+	if _, ok := r.books[entry.BookID]; !ok {
+		t.Errorf("book %d is missing after the change was recorded", entry.BookID)
+	}
+
+	if r.journal != nil {
+		t.Error("journal is left open after the change was recorded")
+	}
+
+	if results := r.search.Search("anna karenina"); len(results) != 1 || results[0].DocID != entry.BookID {
+		t.Errorf("Search = %v, want book %d", results, entry.BookID)
+	}
+}
+
+func TestRollbackOnFailedOperation(t *testing.T) {
+	r := newTestRepository(t)
+	before := stateOf(r)
+
+	// the id is taken before the book is checked
+	book := newTestBook("Anna Karenina")
+	book.PageCount = -1
+	_, err := r.CreateBook(testAdmin, book)
+	if err == nil {
+		t.Fatal("CreateBook accepted a negative page count")
+	}
+
+	if after := stateOf(r); !reflect.DeepEqual(after, before) {
+		t.Errorf("state after the failed change = %+v, want %+v", after, before)
+	}
+}
@@ -0,0 +1,305 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"encoding/base64"
+	"fmt"
+	"github.com/goccy/go-json"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// sortKey extracts the value a list is sorted by. Values are ints or strings.
+type sortKey[T any] func(item T) any
+
+// pageCursor is the position after the last item of a page. It is handed out
+// base64 encoded and is only valid for the same sort order.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value any    `json:"v"`
+	ID    int    `json:"i"`
+}
+
+func (r *Repository) ListBooks(req domain.PageRequest) (*domain.Page[domain.BookEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.BookEntry, 0, len(r.books))
+	for bookID, book := range r.books {
+		items = append(items, domain.BookEntry{BookID: bookID, BookMapField: book})
+	}
+
+	return listPage(items, req, func(item domain.BookEntry) int { return item.BookID }, map[string]sortKey[domain.BookEntry]{
+		"name":             func(item domain.BookEntry) any { return strings.ToLower(item.Name) },
+		"publication_year": func(item domain.BookEntry) any { return item.PublicationYear },
+		"production_id":    func(item domain.BookEntry) any { return item.ProductionID },
+		"language_id":      func(item domain.BookEntry) any { return item.LanguageID },
+	})
+}
+
+func (r *Repository) ListAuthors(req domain.PageRequest) (*domain.Page[domain.AuthorEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.AuthorEntry, 0, len(r.author))
+	for authorID, author := range r.author {
+		items = append(items, domain.AuthorEntry{AuthorID: authorID, AuthorMapField: author})
+	}
+
+	return listPage(items, req, func(item domain.AuthorEntry) int { return item.AuthorID }, map[string]sortKey[domain.AuthorEntry]{
+		"name":    func(item domain.AuthorEntry) any { return strings.ToLower(item.Name) },
+		"surname": func(item domain.AuthorEntry) any { return strings.ToLower(item.Surname) },
+	})
+}
+
+func (r *Repository) ListGenres(req domain.PageRequest) (*domain.Page[domain.GenreEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.GenreEntry, 0, len(r.genres))
+	for genreID, genre := range r.genres {
+		items = append(items, domain.GenreEntry{GenreID: genreID, GenreMapField: genre})
+	}
+
+	return listPage(items, req, func(item domain.GenreEntry) int { return item.GenreID }, map[string]sortKey[domain.GenreEntry]{
+		"name": func(item domain.GenreEntry) any { return strings.ToLower(item.Name) },
+	})
+}
+
+func (r *Repository) ListLanguages(req domain.PageRequest) (*domain.Page[domain.LanguageEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.LanguageEntry, 0, len(r.language))
+	for languageID, language := range r.language {
+		items = append(items, domain.LanguageEntry{LanguageID: languageID, LanguageMapField: language})
+	}
+
+	return listPage(items, req, func(item domain.LanguageEntry) int { return item.LanguageID }, map[string]sortKey[domain.LanguageEntry]{
+		"name": func(item domain.LanguageEntry) any { return strings.ToLower(item.Name) },
+	})
+}
+
+func (r *Repository) ListProductions(req domain.PageRequest) (*domain.Page[domain.ProductionEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.ProductionEntry, 0, len(r.production))
+	for productionID, production := range r.production {
+		items = append(items, domain.ProductionEntry{ProductionID: productionID, ProductionMapField: production})
+	}
+
+	return listPage(items, req, func(item domain.ProductionEntry) int { return item.ProductionID }, map[string]sortKey[domain.ProductionEntry]{
+		"name": func(item domain.ProductionEntry) any { return strings.ToLower(item.Name) },
+	})
+}
+
+func (r *Repository) ListInstances(req domain.PageRequest) (*domain.Page[domain.InstanceEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.InstanceEntry, 0, len(r.instance))
+	for instanceID, instance := range r.instance {
+		items = append(items, domain.InstanceEntry{InstanceID: instanceID, InstanceMapField: instance})
+	}
+
+	return listPage(items, req, func(item domain.InstanceEntry) int { return item.InstanceID }, map[string]sortKey[domain.InstanceEntry]{
+		"book_id":           func(item domain.InstanceEntry) any { return item.BookID },
+		"status":            func(item domain.InstanceEntry) any { return string(item.Status) },
+		"home_branch_id":    func(item domain.InstanceEntry) any { return item.HomeBranchID },
+		"current_branch_id": func(item domain.InstanceEntry) any { return item.CurrentBranchID },
+	})
+}
+
+func (r *Repository) ListUsers(req domain.PageRequest) (*domain.Page[domain.UserEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireBans(now)
+	r.expireSessions(now)
+
+	items := make([]domain.UserEntry, 0, len(r.user))
+	for userID, user := range r.user {
+		items = append(items, domain.UserEntry{UserID: userID, UserMapField: user})
+	}
+
+	return listPage(items, req, func(item domain.UserEntry) int { return item.UserID }, map[string]sortKey[domain.UserEntry]{
+		"name":          func(item domain.UserEntry) any { return strings.ToLower(item.Name) },
+		"login_status":  func(item domain.UserEntry) any { return item.LoginStatus },
+		"register_date": func(item domain.UserEntry) any { return sortableDate(item.RegisterDate) },
+	})
+}
+
+func (r *Repository) ListReaders(req domain.PageRequest) (*domain.Page[domain.ReaderEntry], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]domain.ReaderEntry, 0, len(r.reader))
+	for readerID, reader := range r.reader {
+		items = append(items, domain.ReaderEntry{ReaderID: readerID, ReaderMapField: reader})
+	}
+
+	return listPage(items, req, func(item domain.ReaderEntry) int { return item.ReaderID }, map[string]sortKey[domain.ReaderEntry]{
+		"category": func(item domain.ReaderEntry) any { return item.Category },
+		"loans":    func(item domain.ReaderEntry) any { return len(item.InstanceID) },
+	})
+}
+
+// listPage sorts the items by the requested key, the id being both the
+// default key and the tie breaker, and cuts out the requested page.
+func listPage[T any](items []T, req domain.PageRequest, id func(T) int, keys map[string]sortKey[T]) (*domain.Page[T], error) {
+	if req.Sort == "" {
+		req.Sort = "id"
+	}
+
+	key, ok := keys[req.Sort]
+	if req.Sort == "id" {
+		key, ok = func(item T) any { return id(item) }, true
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown sort key: %s", req.Sort)
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultPageLimit
+	}
+
+	if req.Limit < 0 || req.Limit > maxPageLimit || req.Offset < 0 {
+		return nil, fmt.Errorf("invalid limit or offset")
+	}
+
+	// less orders two positions ascending, or descending with req.Desc.
+	less := func(leftValue any, leftID int, rightValue any, rightID int) bool {
+		order := compareSortValues(leftValue, rightValue)
+		if order == 0 {
+			order = compareSortValues(leftID, rightID)
+		}
+
+		if req.Desc {
+			return order > 0
+		}
+
+		return order < 0
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return less(key(items[i]), id(items[i]), key(items[j]), id(items[j]))
+	})
+
+	start := req.Offset
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursor.Sort != req.Sort || cursor.Desc != req.Desc {
+			return nil, fmt.Errorf("cursor does not match the sort order")
+		}
+
+		// Continue after the cursor position rather than at an index, so
+		// items added or removed in between do not shift the pages.
+		start = sort.Search(len(items), func(i int) bool {
+			return less(cursor.Value, cursor.ID, key(items[i]), id(items[i]))
+		})
+	}
+
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := start + req.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := domain.Page[T]{
+		Total: len(items),
+		Items: items[start:end],
+	}
+
+	if end < len(items) && end > start {
+		last := items[end-1]
+		page.NextCursor = encodeCursor(pageCursor{
+			Sort:  req.Sort,
+			Desc:  req.Desc,
+			Value: key(last),
+			ID:    id(last),
+		})
+	}
+
+	return &page, nil
+}
+
+// compareSortValues compares two ints or two strings.
+func compareSortValues(left, right any) int {
+	switch left := left.(type) {
+	case int:
+		right, _ := right.(int)
+		switch {
+		case left < right:
+			return -1
+		case left > right:
+			return 1
+		}
+
+		return 0
+
+	case string:
+		right, _ := right.(string)
+		return strings.Compare(left, right)
+
+	default:
+		return 0
+	}
+}
+
+// sortableDate turns a dd-mm-yyyy date of users.json into yyyy-mm-dd, which
+// sorts chronologically as a string. Other values are left as they are.
+func sortableDate(date string) string {
+	parsed, err := time.Parse("02-01-2006", date)
+	if err != nil {
+		return date
+	}
+
+	return parsed.Format("2006-01-02")
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor reads a cursor back. JSON numbers decode as float64, so int
+// sort values are converted back to int.
+func decodeCursor(value string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor pageCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	if number, ok := cursor.Value.(float64); ok {
+		cursor.Value = int(number)
+	}
+
+	return &cursor, nil
+}
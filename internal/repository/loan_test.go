@@ -0,0 +1,143 @@
+package book_inventory_system_repository
+
+import (
+	"testing"
+	"time"
+)
+
+const testGracePeriod = 3 * 24 * time.Hour
+
+func TestRenewLoan(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		// due is when the loan falls due, relative to the renewal
+		due     time.Duration
+		prepare func(t *testing.T, r *Repository, loanID int)
+		wantErr bool
+		charged int
+	}{
+		{name: "before the due date", due: day},
+		{name: "within the grace period", due: -36 * time.Hour, charged: 2 * testFineRate},
+		{name: "past the grace period", due: -4 * day, wantErr: true},
+		{
+			name: "renewals used up",
+			due:  day,
+			prepare: func(t *testing.T, r *Repository, loanID int) {
+				for i := 0; i < testTerms.MaxRenewals; i++ {
+					if _, err := r.RenewLoan(testReaderID, loanID); err != nil {
+						t.Fatalf("RenewLoan: %v", err)
+					}
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "book held",
+			due:  day,
+			prepare: func(t *testing.T, r *Repository, loanID int) {
+				if _, err := r.PlaceHold(testOtherID, testBookID); err != nil {
+					t.Fatalf("PlaceHold: %v", err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "loan of another reader",
+			due:  day,
+			prepare: func(t *testing.T, r *Repository, loanID int) {
+				loan := r.loan[loanID]
+				loan.ReaderID = testOtherID
+				r.loan[loanID] = loan
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t, WithRenewals(testTerms.MaxRenewals, testGracePeriod))
+			_, loanID := lend(t, r, testReaderID, time.Now().Add(tt.due))
+			if tt.prepare != nil {
+				tt.prepare(t, r, loanID)
+			}
+
+			previous := r.loan[loanID]
+			loan, err := r.RenewLoan(testReaderID, loanID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenewLoan = %v, want error %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !r.loan[loanID].DueDate.Equal(previous.DueDate) {
+					t.Errorf("refused renewal moved the due date to %v", r.loan[loanID].DueDate)
+				}
+
+				return
+			}
+
+			if want := previous.DueDate.Add(testTerms.LoanPeriod); !loan.DueDate.Equal(want) {
+				t.Errorf("due date = %v, want %v", loan.DueDate, want)
+			}
+
+			if account := r.fineAccount(testReaderID, time.Now()); account.Charged != tt.charged {
+				t.Errorf("charged %d, want %d", account.Charged, tt.charged)
+			}
+		})
+	}
+}
+
+func TestFineAccrual(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		// due is when the loan falls due, renewed within the grace period
+		// when renew is set, and overdue how long past the due date it is
+		// returned
+		due     time.Duration
+		renew   bool
+		overdue time.Duration
+		charged int
+	}{
+		{name: "returned in time", due: day, overdue: -time.Hour},
+		{name: "returned late", due: day, overdue: 36 * time.Hour, charged: 2 * testFineRate},
+		{name: "returned late past the cap", due: day, overdue: 30 * day, charged: testFineCap},
+		{name: "renewed late, returned in time", due: -36 * time.Hour, renew: true, overdue: -time.Hour, charged: 2 * testFineRate},
+		{name: "renewed late, returned late", due: -36 * time.Hour, renew: true, overdue: 12 * time.Hour, charged: 3 * testFineRate},
+		{name: "renewed late, returned past the cap", due: -36 * time.Hour, renew: true, overdue: 30 * day, charged: testFineCap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t, WithRenewals(testTerms.MaxRenewals, testGracePeriod))
+			instanceID, loanID := lend(t, r, testReaderID, time.Now().Add(tt.due))
+
+			if tt.renew {
+				if _, err := r.RenewLoan(testReaderID, loanID); err != nil {
+					t.Fatalf("RenewLoan: %v", err)
+				}
+			}
+
+			// move the loan in time so that it is returned now
+			loan := r.loan[loanID]
+			shift := time.Now().Add(-tt.overdue).Sub(loan.DueDate)
+			loan.DueDate = loan.DueDate.Add(shift)
+			r.loan[loanID] = loan
+
+			if account := r.fineAccount(testReaderID, time.Now()); account.Balance != tt.charged {
+				t.Errorf("balance before the return = %d, want %d", account.Balance, tt.charged)
+			}
+
+			if err := r.ReturnBook(testReaderID, instanceID); err != nil {
+				t.Fatalf("ReturnBook: %v", err)
+			}
+
+			account := r.fineAccount(testReaderID, time.Now())
+			if account.Charged != tt.charged || account.Accruing != 0 {
+				t.Errorf("charged %d accruing %d, want %d charged", account.Charged, account.Accruing, tt.charged)
+			}
+		})
+	}
+}
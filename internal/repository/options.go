@@ -2,13 +2,241 @@ package book_inventory_system_repository
 
 import (
 	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
 	"fmt"
 	"github.com/goccy/go-json"
 	"os"
+	"strings"
+	"time"
 )
 
 type Option func(repository *Repository) error
 
+// WithLoanPeriod sets the loan period used for new loans. It has to be passed
+// before WithDump so that loans restored from readers.json get the same period.
+func WithLoanPeriod(period time.Duration) Option {
+	return func(r *Repository) error {
+		if period <= 0 {
+			return fmt.Errorf("invalid loan period: %s", period)
+		}
+
+		r.loanPeriod = period
+		return nil
+	}
+}
+
+// WithDefaultCategories sets the lending categories of readers and books that
+// have none in the dump. It has to be passed before WithDump.
+func WithDefaultCategories(readerCategory, itemCategory string) Option {
+	return func(r *Repository) error {
+		if readerCategory == "" || itemCategory == "" {
+			return fmt.Errorf("invalid default categories")
+		}
+
+		r.defaultReaderCategory = readerCategory
+		r.defaultItemCategory = itemCategory
+		return nil
+	}
+}
+
+// WithRenewals sets how many times a loan can be renewed and how long past its
+// due date an overdue loan can still be renewed.
+func WithRenewals(maxRenewals int, gracePeriod time.Duration) Option {
+	return func(r *Repository) error {
+		if maxRenewals < 0 || gracePeriod < 0 {
+			return fmt.Errorf("invalid renewal settings")
+		}
+
+		r.maxRenewals = maxRenewals
+		r.renewalGracePeriod = gracePeriod
+		return nil
+	}
+}
+
+// WithHoldPickupWindow sets how long a copy stays on the hold shelf before the
+// hold expires and the copy goes to the next reader in the queue.
+func WithHoldPickupWindow(window time.Duration) Option {
+	return func(r *Repository) error {
+		if window <= 0 {
+			return fmt.Errorf("invalid hold pickup window: %s", window)
+		}
+
+		r.holdPickupWindow = window
+		return nil
+	}
+}
+
+// WithFines sets the daily fine rate and the fine cap of new loans, and the
+// outstanding balance above which a reader is not allowed to borrow. Amounts
+// are in minor currency units.
+func WithFines(dailyRate, fineCap, threshold int) Option {
+	return func(r *Repository) error {
+		if dailyRate < 0 || fineCap < 0 || threshold < 0 {
+			return fmt.Errorf("invalid fine settings")
+		}
+
+		r.fineDailyRate = dailyRate
+		r.fineCap = fineCap
+		r.fineThreshold = threshold
+		return nil
+	}
+}
+
+// WithBranchDump loads the library branches. Copies that have no branch in
+// instances.json belong to the default branch, so it has to be passed before
+// WithDump.
+func WithBranchDump(branchDumpFilePath string, defaultBranchID int) Option {
+	return func(r *Repository) error {
+		branches, err := branchDump(branchDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("branches.json dump error: %w", err)
+		}
+
+		checker := r.newIDChecker(make(rejectedRecords), branchesFile, "branch_id")
+		for record, branch := range branches.Branches {
+			if !checker.check(record, branch.BranchID) {
+				continue
+			}
+
+			r.branch[branch.BranchID] = domain.BranchMapField{
+				Name:    branch.Name,
+				Address: branch.Address,
+			}
+		}
+
+		if _, ok := r.branch[defaultBranchID]; !ok {
+			return fmt.Errorf("default branch %d not found", defaultBranchID)
+		}
+
+		r.defaultBranchID = defaultBranchID
+		return nil
+	}
+}
+
+// WithFunds sets the acquisitions budget of every fund, in minor currency
+// units.
+func WithFunds(budgets map[string]int) Option {
+	return func(r *Repository) error {
+		for name, budget := range budgets {
+			if name == "" || budget < 0 {
+				return fmt.Errorf("invalid fund %q: %d", name, budget)
+			}
+
+			r.fund[name] = domain.FundMapField{
+				Name:    name,
+				Budget:  budget,
+				Balance: budget,
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithSeriesDump loads the book series. Books are checked against the series
+// when they are loaded, so it has to be passed before WithDump.
+func WithSeriesDump(seriesDumpFilePath string) Option {
+	return func(r *Repository) error {
+		series, err := seriesDump(seriesDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("series.json dump error: %w", err)
+		}
+
+		checker := r.newIDChecker(make(rejectedRecords), seriesFile, "series_id")
+		for record, s := range series.Series {
+			if !checker.check(record, s.SeriesID) {
+				continue
+			}
+
+			r.series[s.SeriesID] = domain.SeriesMapField{
+				Name:        s.Name,
+				Description: s.Description,
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithStrictValidation makes WithDump fail when the dumps have integrity
+// violations. It has to be passed before WithDump.
+func WithStrictValidation(strict bool) Option {
+	return func(r *Repository) error {
+		r.strictValidation = strict
+		return nil
+	}
+}
+
+// WithDeleteRules overrides the default restrict rule of the given
+// relations, e.g. "book.instances": "cascade".
+func WithDeleteRules(rules map[string]string) Option {
+	return func(r *Repository) error {
+		for relation, rule := range rules {
+			allowed, ok := domain.DeleteRules[relation]
+			if !ok {
+				return fmt.Errorf("unknown delete relation %q", relation)
+			}
+
+			valid := false
+			for _, a := range allowed {
+				if string(a) == rule {
+					valid = true
+					break
+				}
+			}
+
+			if !valid {
+				return fmt.Errorf("delete rule %q is not allowed for %s, use one of %v", rule, relation, allowed)
+			}
+
+			r.deleteRules[relation] = domain.DeleteRule(rule)
+		}
+
+		return nil
+	}
+}
+
+// WithSessions sets how long a session lasts at most and how long it lasts
+// without being used.
+func WithSessions(ttl, idleTimeout time.Duration) Option {
+	return func(r *Repository) error {
+		if ttl <= 0 || idleTimeout <= 0 {
+			return fmt.Errorf("invalid session ttl %v or idle timeout %v", ttl, idleTimeout)
+		}
+
+		r.sessionTTL = ttl
+		r.sessionIdleTimeout = idleTimeout
+		return nil
+	}
+}
+
+// WithReadOnlyDumps keeps WithDump from writing migrated data back to the
+// dumps. It has to be passed before WithDump.
+func WithReadOnlyDumps(readOnly bool) Option {
+	return func(r *Repository) error {
+		r.readOnlyDumps = readOnly
+		return nil
+	}
+}
+
+// WithAuditLog records privileged changes in the hash-chained log at path.
+// An empty path records nothing.
+func WithAuditLog(path string) Option {
+	return func(r *Repository) error {
+		if path == "" {
+			return nil
+		}
+
+		log, err := audit.Open(path)
+		if err != nil {
+			return fmt.Errorf("audit log error: %w", err)
+		}
+
+		r.audit = log
+		return nil
+	}
+}
+
 func WithDump(
 	adminDumpFilePath,
 	authorDumpFilePath,
@@ -26,16 +254,63 @@ func WithDump(
 			return fmt.Errorf("admins.json dump error: %w", err)
 		}
 
-		for _, admin := range admins.Admins {
-			r.admins[admin.AdminID] = domain.AdminMapField{}
-		}
-
 		authors, err := authorDump(authorDumpFilePath)
 		if err != nil {
 			return fmt.Errorf("authors.json dump error: %w", err)
 		}
 
-		for _, author := range authors.Authors {
+		books, err := bookDump(bookDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("books.json dump error: %w", err)
+		}
+
+		genres, err := genreDump(genreDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("genres.json dump error: %w", err)
+		}
+
+		instances, err := instanceDump(instanceDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("instances.json dump error: %w", err)
+		}
+
+		languages, err := languageDump(languageDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("languages.json dump error: %w", err)
+		}
+
+		productions, err := productionDump(productionDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("productions.json dump error: %w", err)
+		}
+
+		readers, err := readerDump(readerDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("readers.json dump error: %w", err)
+		}
+
+		users, err := userDump(userDumpFilePath)
+		if err != nil {
+			return fmt.Errorf("users.json dump error: %w", err)
+		}
+
+		// records with an invalid or duplicate id are reported and left out,
+		// so that they cannot stand in for the record they collide with
+		rejected := r.checkDumpIDs(admins, authors, books, genres, instances, languages, productions, readers, users)
+
+		for record, admin := range admins.Admins {
+			if rejected.has(adminsFile, record) {
+				continue
+			}
+
+			r.admins[admin.AdminID] = domain.AdminMapField{}
+		}
+
+		for record, author := range authors.Authors {
+			if rejected.has(authorsFile, record) {
+				continue
+			}
+
 			r.author[author.AuthorID] = domain.AuthorMapField{
 				Name:         author.Name,
 				Surname:      author.Surname,
@@ -44,92 +319,199 @@ func WithDump(
 			}
 		}
 
-		books, err := bookDump(bookDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("books.json dump error: %w", err)
-		}
+		for record, book := range books.Books {
+			if rejected.has(booksFile, record) {
+				continue
+			}
 
-		for _, book := range books.Books {
-			r.books[book.BookID] = domain.BookMapField{
-				Name:         book.Name,
-				AuthorID:     book.AuthorID,
-				GenreID:      book.GenreID,
-				ProductionID: book.ProductionID,
-				LanguageID:   book.LanguageID,
-				Description:  book.Description,
+			// Books in the legacy format have a single author_id and genre_id
+			// instead of the authors and genre_ids lists.
+			authors := book.Authors
+			if len(authors) == 0 && book.AuthorID != 0 {
+				authors = []domain.BookAuthor{{AuthorID: book.AuthorID}}
 			}
-		}
 
-		genres, err := genreDump(genreDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("genres.json dump error: %w", err)
+			genreIDs := book.GenreIDs
+			if len(genreIDs) == 0 && book.GenreID != 0 {
+				genreIDs = []int{book.GenreID}
+			}
+
+			normalized, err := r.normalizeBook(book.BookID, domain.BookMapField{
+				Name:            book.Name,
+				Authors:         authors,
+				GenreIDs:        genreIDs,
+				ProductionID:    book.ProductionID,
+				LanguageID:      book.LanguageID,
+				Description:     book.Description,
+				Category:        book.Category,
+				ISBN:            book.ISBN,
+				PublicationYear: book.PublicationYear,
+				Edition:         book.Edition,
+				PageCount:       book.PageCount,
+				Format:          book.Format,
+				SeriesID:        book.SeriesID,
+				Volume:          book.Volume,
+			})
+			if err != nil {
+				r.addViolation(booksFile, record, book.BookID, "%v, the book is not loaded", err)
+				continue
+			}
+
+			r.books[book.BookID] = normalized
+			if normalized.ISBN != "" {
+				r.isbn[normalized.ISBN] = book.BookID
+			}
 		}
 
-		for _, genre := range genres.Genres {
+		for record, genre := range genres.Genres {
+			if rejected.has(genresFile, record) {
+				continue
+			}
+
 			r.genres[genre.GenreID] = domain.GenreMapField{
 				Name: genre.Name,
 			}
 		}
 
-		instances, err := instanceDump(instanceDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("instances.json dump error: %w", err)
-		}
+		for record, instance := range instances.Instances {
+			if rejected.has(instancesFile, record) {
+				continue
+			}
+
+			if instance.HomeBranchID == 0 {
+				instance.HomeBranchID = r.defaultBranchID
+			}
+
+			if instance.CurrentBranchID == 0 {
+				instance.CurrentBranchID = instance.HomeBranchID
+			}
+
+			var status domain.InstanceStatus
+			if len(instance.Status) == 0 {
+				err = fmt.Errorf("missing status")
+			} else {
+				err = status.UnmarshalJSON(instance.Status)
+			}
+
+			if err != nil {
+				r.addViolation(instancesFile, record, instance.InstanceID, "%v, the instance is loaded as %s", err, domain.InstanceInRepair)
+				status = domain.InstanceInRepair
+			}
 
-		for _, instance := range instances.Instances {
 			r.instance[instance.InstanceID] = domain.InstanceMapField{
-				BookID: instance.BookID,
-				Status: instance.Status,
+				BookID:          instance.BookID,
+				Status:          status,
+				HomeBranchID:    instance.HomeBranchID,
+				CurrentBranchID: instance.CurrentBranchID,
 			}
 		}
 
-		languages, err := languageDump(languageDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("languages.json dump error: %w", err)
-		}
+		for record, language := range languages.Languages {
+			if rejected.has(languagesFile, record) {
+				continue
+			}
 
-		for _, language := range languages.Languages {
 			r.language[language.LanguageID] = domain.LanguageMapField{
 				Name: language.Name,
 			}
 		}
 
-		productions, err := productionDump(productionDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("productions.json dump error: %w", err)
-		}
+		for record, production := range productions.Productions {
+			if rejected.has(productionsFile, record) {
+				continue
+			}
 
-		for _, production := range productions.Productions {
 			r.production[production.ProductionID] = domain.ProductionMapField{
 				Name: production.Name,
 			}
 		}
 
-		readers, err := readerDump(readerDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("readers.json dump error: %w", err)
-		}
+		loadDate := time.Now()
+		for record, reader := range readers.Readers {
+			if rejected.has(readersFile, record) {
+				continue
+			}
+
+			if reader.Category == "" {
+				reader.Category = r.defaultReaderCategory
+			}
 
-		for _, reader := range readers.Readers {
 			r.reader[reader.ReaderID] = domain.ReaderMapField{
 				InstanceID: reader.InstanceID,
+				Category:   reader.Category,
 			}
-		}
 
-		users, err := userDump(userDumpFilePath)
-		if err != nil {
-			return fmt.Errorf("users.json dump error: %w", err)
+			// readers.json has no loan history, so every copy a reader holds
+			// is restored as an open loan starting at load time.
+			for _, instanceID := range reader.InstanceID {
+				instance, ok := r.instance[instanceID]
+				if !ok || instance.Status != domain.InstanceOnLoan {
+					continue
+				}
+
+				if _, ok = r.openLoanByInstance(instanceID); ok {
+					continue
+				}
+
+				r.openLoan(reader.ReaderID, instanceID, loadDate, r.defaultTerms(instanceID))
+			}
 		}
 
-		for _, user := range users.Users {
+		// plaintext passwords of legacy dumps are hashed, by record
+		hashes := make(map[int]string)
+		for record, user := range users.Users {
+			password := user.Password
+			if !isPasswordHash(password) {
+				password, err = hashPassword(password)
+				if err != nil {
+					return fmt.Errorf("users.json password error: %w", err)
+				}
+
+				hashes[record] = password
+			}
+
+			if rejected.has(usersFile, record) {
+				continue
+			}
+
+			// sessions do not survive a restart, so nobody is logged in
 			r.user[user.UserID] = domain.UserMapField{
 				Name:         user.Name,
-				Password:     user.Password,
-				LoginStatus:  user.LoginStatus,
+				Password:     password,
+				LoginStatus:  loggedOut,
 				RegisterDate: user.RegisterDate,
 			}
 		}
 
+		for bookID := range r.books {
+			r.indexBook(bookID)
+		}
+
+		r.bookSeq = maxMapID(r.books)
+		r.authorSeq = maxMapID(r.author)
+		r.genreSeq = maxMapID(r.genres)
+		r.languageSeq = maxMapID(r.language)
+		r.productionSeq = maxMapID(r.production)
+		r.instanceSeq = maxMapID(r.instance)
+
+		r.checkDumpReferences(rejected, admins, authors, books, instances, readers)
+
+		if r.strictValidation && len(r.violations) > 0 {
+			messages := make([]string, 0, len(r.violations))
+			for _, violation := range r.violations {
+				messages = append(messages, violation.String())
+			}
+
+			return fmt.Errorf("%d integrity violations:\n%s", len(r.violations), strings.Join(messages, "\n"))
+		}
+
+		if len(hashes) > 0 && !r.readOnlyDumps {
+			err = migrateUserDump(userDumpFilePath, hashes)
+			if err != nil {
+				return fmt.Errorf("users.json migration error: %w", err)
+			}
+		}
+
 		return nil
 	}
 }
@@ -268,3 +650,33 @@ func userDump(filepath string) (*domain.User, error) {
 
 	return &dump, nil
 }
+
+func branchDump(filepath string) (*domain.Branch, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump domain.Branch
+	err = json.Unmarshal(data, &dump)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dump, nil
+}
+
+func seriesDump(filepath string) (*domain.Series, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump domain.Series
+	err = json.Unmarshal(data, &dump)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dump, nil
+}
@@ -0,0 +1,108 @@
+package book_inventory_system_repository
+
+import (
+	"fmt"
+	"github.com/goccy/go-json"
+	"golang.org/x/crypto/bcrypt"
+	"os"
+	"path/filepath"
+)
+
+// VerifyPassword reports whether the password is the one of the user. The
+// comparison is deliberately slow, so it runs without holding r.mu.
+func (r *Repository) VerifyPassword(userID int, password string) (bool, error) {
+	r.mu.Lock()
+	user, ok := r.user[userID]
+	r.mu.Unlock()
+
+	if !ok {
+		return false, fmt.Errorf("user not found")
+	}
+
+	return comparePassword(user.Password, password), nil
+}
+
+// dummyPasswordHash is compared against when there is no user to compare
+// with, so that looking a user up takes as long either way.
+var dummyPasswordHash, _ = hashPassword("dummy password")
+
+func comparePassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// isPasswordHash tells a bcrypt hash from a plaintext password of a legacy
+// dump.
+func isPasswordHash(password string) bool {
+	_, err := bcrypt.Cost([]byte(password))
+	return err == nil
+}
+
+// migrateUserDump rewrites the users dump with the passwords of the given
+// records, by position, replaced by their hashes. The other fields of the
+// records are kept as they are, including misspelled keys.
+func migrateUserDump(path string, hashes map[int]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var dump struct {
+		Users []map[string]json.RawMessage `json:"users"`
+	}
+	err = json.Unmarshal(data, &dump)
+	if err != nil {
+		return err
+	}
+
+	for record, hash := range hashes {
+		if record >= len(dump.Users) {
+			return fmt.Errorf("record %d not found", record+1)
+		}
+
+		dump.Users[record]["password"], err = json.Marshal(hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err = json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	// the dump is replaced at once, so a failed write cannot lose users
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(append(data, '\n'))
+	if err == nil {
+		err = file.Chmod(info.Mode())
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,40 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"time"
+)
+
+// Principal returns the user with the roles they have now, so that a role
+// or a ban takes effect on the next request rather than the next login.
+func (r *Repository) Principal(userID int) (*domain.Principal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireBans(time.Now())
+
+	user, ok := r.user[userID]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if user.Banned {
+		return nil, fmt.Errorf("user is banned")
+	}
+
+	principal := domain.Principal{
+		UserID: userID,
+		Roles:  make([]string, 0),
+	}
+
+	if _, ok = r.admins[userID]; ok {
+		principal.Roles = append(principal.Roles, domain.RoleAdmin)
+	}
+
+	if _, ok = r.reader[userID]; ok {
+		principal.Roles = append(principal.Roles, domain.RoleReader)
+	}
+
+	return &principal, nil
+}
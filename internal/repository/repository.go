@@ -2,21 +2,36 @@ package book_inventory_system_repository
 
 import (
 	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
+	search "book-inventory-system/pkg/search"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 const (
-	inUse int = iota
-	inLibrary
-	outOfUser
+	loggedIn  = "login"
+	loggedOut = "logout"
+)
+
+const (
+	defaultLoanPeriod       = 14 * 24 * time.Hour
+	defaultHoldPickupWindow = 3 * 24 * time.Hour
+	defaultMaxRenewals      = 2
+	defaultCategory         = "standard"
+
+	defaultSessionTTL         = 24 * time.Hour
+	defaultSessionIdleTimeout = 30 * time.Minute
 )
 
 type Repository struct {
 	mu         *sync.Mutex
 	admins     map[int]domain.AdminMapField
 	books      map[int]domain.BookMapField
+	isbn       map[string]int
 	genres     map[int]domain.GenreMapField
 	language   map[int]domain.LanguageMapField
 	author     map[int]domain.AuthorMapField
@@ -24,6 +39,67 @@ type Repository struct {
 	instance   map[int]domain.InstanceMapField
 	user       map[int]domain.UserMapField
 	reader     map[int]domain.ReaderMapField
+	loan       map[int]domain.LoanMapField
+	loanSeq    int
+	loanPeriod time.Duration
+
+	// ids of catalog entities are never reused, so that closed loans, order
+	// lines and audit entries keep pointing at what they were made for
+	bookSeq       int
+	authorSeq     int
+	genreSeq      int
+	languageSeq   int
+	productionSeq int
+	instanceSeq   int
+
+	defaultReaderCategory string
+	defaultItemCategory   string
+
+	maxRenewals        int
+	renewalGracePeriod time.Duration
+
+	hold             map[int]domain.HoldMapField
+	holdSeq          int
+	holdPickupWindow time.Duration
+
+	fine          map[int]domain.FineMapField
+	fineSeq       int
+	fineDailyRate int
+	fineCap       int
+	fineThreshold int
+
+	stocktake    map[int]domain.StocktakeMapField
+	stocktakeSeq int
+
+	branch          map[int]domain.BranchMapField
+	defaultBranchID int
+	transfer        map[int]domain.TransferMapField
+	transferSeq     int
+
+	series map[int]domain.SeriesMapField
+
+	search *search.Index
+
+	violations       []domain.Violation
+	strictValidation bool
+	readOnlyDumps    bool
+
+	deleteRules map[string]domain.DeleteRule
+
+	fund     map[string]domain.FundMapField
+	order    map[int]domain.OrderMapField
+	orderSeq int
+
+	ban    map[int]domain.BanMapField
+	banSeq int
+
+	audit   *audit.Log
+	journal *journal
+
+	session            map[string]domain.SessionMapField
+	sessionSeq         int
+	sessionTTL         time.Duration
+	sessionIdleTimeout time.Duration
 }
 
 func New(opts ...Option) (*Repository, error) {
@@ -33,6 +109,7 @@ func New(opts ...Option) (*Repository, error) {
 
 	r.admins = make(map[int]domain.AdminMapField)
 	r.books = make(map[int]domain.BookMapField)
+	r.isbn = make(map[string]int)
 	r.genres = make(map[int]domain.GenreMapField)
 	r.language = make(map[int]domain.LanguageMapField)
 	r.author = make(map[int]domain.AuthorMapField)
@@ -40,6 +117,29 @@ func New(opts ...Option) (*Repository, error) {
 	r.instance = make(map[int]domain.InstanceMapField)
 	r.user = make(map[int]domain.UserMapField)
 	r.reader = make(map[int]domain.ReaderMapField)
+	r.loan = make(map[int]domain.LoanMapField)
+	r.loanPeriod = defaultLoanPeriod
+	r.maxRenewals = defaultMaxRenewals
+	r.defaultReaderCategory = defaultCategory
+	r.defaultItemCategory = defaultCategory
+	r.hold = make(map[int]domain.HoldMapField)
+	r.holdPickupWindow = defaultHoldPickupWindow
+	r.fine = make(map[int]domain.FineMapField)
+	r.stocktake = make(map[int]domain.StocktakeMapField)
+	r.branch = make(map[int]domain.BranchMapField)
+	r.transfer = make(map[int]domain.TransferMapField)
+	r.series = make(map[int]domain.SeriesMapField)
+	r.search = search.New(searchWeights)
+	r.fund = make(map[string]domain.FundMapField)
+	r.order = make(map[int]domain.OrderMapField)
+	r.ban = make(map[int]domain.BanMapField)
+	r.session = make(map[string]domain.SessionMapField)
+	r.sessionTTL = defaultSessionTTL
+	r.sessionIdleTimeout = defaultSessionIdleTimeout
+	r.deleteRules = make(map[string]domain.DeleteRule)
+	for relation, rules := range domain.DeleteRules {
+		r.deleteRules[relation] = rules[0]
+	}
 
 	for _, opt := range opts {
 		err := opt(r)
@@ -51,110 +151,94 @@ func New(opts ...Option) (*Repository, error) {
 	return r, errors.Join(errs...)
 }
 
-func (r *Repository) ReturnBook(id int) error {
+func (r *Repository) ReturnBook(readerID, instanceID int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	instance, ok := r.instance[id]
+	reader, ok := r.reader[readerID]
 	if !ok {
-		return fmt.Errorf("instance not found")
-	}
-
-	switch instance.Status {
-	case inUse:
-		instance.Status = inLibrary
-		r.instance[id] = instance
-		return nil
-	default:
-		return fmt.Errorf("instance already in library")
+		return fmt.Errorf("reader not found")
 	}
-}
-
-func (r *Repository) TakeBook(id int) (*domain.BookMapField, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
 
-	instance, ok := r.instance[id]
+	instance, ok := r.instance[instanceID]
 	if !ok {
-		return nil, fmt.Errorf("instance not found")
+		return fmt.Errorf("instance not found")
 	}
 
-	isFound := false
-	switch instance.Status {
-	case inLibrary:
-		for bookID := range r.books {
-			if instance.BookID == bookID {
-				isFound = true
-				book := r.books[bookID]
-				instance.Status = inUse
-				r.instance[id] = instance
-				return &book, nil
-			}
-		}
-	default:
-		return nil, fmt.Errorf("you can`t take an instance")
+	if instance.Status != domain.InstanceOnLoan {
+		return fmt.Errorf("instance is not on loan")
 	}
 
-	if !isFound {
-		return nil, fmt.Errorf("instance not found")
+	loanID, ok := r.openLoanByInstance(instanceID)
+	if !ok || r.loan[loanID].ReaderID != readerID {
+		return fmt.Errorf("reader does not hold the instance")
 	}
 
-	return nil, nil
+	now := time.Now()
+	loan := r.loan[loanID]
+	loan.ReturnDate = &now
+	put(r, r.loan, loanID, loan)
+	r.chargeOverdueFine(loan)
+
+	reader.InstanceID = removeID(reader.InstanceID, instanceID)
+	put(r, r.reader, readerID, reader)
+
+	r.expireHolds(now)
+
+	return r.shelveInstance(instanceID, now)
 }
 
-func (r *Repository) UpdateLoginStatus(id int, status string) error {
+// TakeBook lends the instance to the reader on the given terms. The loan limit
+// of the terms is checked here again so that concurrent checkouts cannot
+// exceed it.
+func (r *Repository) TakeBook(readerID, instanceID int, terms domain.LoanTerms) (*domain.BookMapField, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	user, ok := r.user[id]
-	if !ok {
-		return fmt.Errorf("user not found")
+	now := time.Now()
+	r.expireHolds(now)
+
+	problems := r.checkoutProblems(readerID, instanceID, now)
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
 	}
 
-	if user.LoginStatus != status {
-		user.LoginStatus = status
-		r.user[id] = user
-	} else {
-		return fmt.Errorf("already %s", status)
+	loan, err := r.takeInstance(readerID, instanceID, terms, now)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	book := r.books[r.instance[loan.InstanceID].BookID]
+	return &book, nil
 }
 
-func (r *Repository) BanUser(userID, adminID int) error {
+// UpdateInstanceStatus changes the status by hand. Copies get on loan, on
+// the hold shelf and in transit only through checkouts, holds and transfers,
+// and the only way out of those statuses by hand is to declare the copy lost,
+// which closes its loan, hold or transfer. A copy taken off the shelf is no
+// longer reserved for a transfer, and one put back in circulation is shelved
+// like a returned one, for the first reader in the hold queue.
+func (r *Repository) UpdateInstanceStatus(actor domain.Actor, instanceID int, status domain.InstanceStatus) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	fmt.Println(r.user)
-	_, ok := r.admins[adminID]
-	if !ok {
-		return fmt.Errorf("invalid admin id")
-	}
-
-	_, ok = r.admins[adminID]
+	_, ok := r.admins[actor.UserID]
 	if !ok {
 		return fmt.Errorf("permission denied")
 	}
 
-	_, ok = r.user[userID]
-	if !ok {
-		return fmt.Errorf("user not found")
-	}
+	r.begin()
+	defer r.rollback()
 
-	delete(r.user, userID)
-	return nil
-}
-
-func (r *Repository) UpdateInstanceStatus(instanceID, status int) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	now := time.Now()
+	r.expireHolds(now)
 
 	instance, ok := r.instance[instanceID]
 	if !ok {
 		return fmt.Errorf("instance not found")
 	}
 
-	if status < 0 || status > 3 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status")
 	}
 
@@ -162,8 +246,46 @@ func (r *Repository) UpdateInstanceStatus(instanceID, status int) error {
 		return fmt.Errorf("already in status")
 	}
 
-	instance.Status = status
-	r.instance[instanceID] = instance
+	switch status {
+	case domain.InstanceOnLoan, domain.InstanceOnHoldShelf, domain.InstanceInTransit:
+		return fmt.Errorf("status %s is set by checkouts, holds and transfers only", status)
+	}
+
+	switch instance.Status {
+	case domain.InstanceOnLoan, domain.InstanceOnHoldShelf, domain.InstanceInTransit:
+		if status != domain.InstanceLost {
+			return fmt.Errorf("instance is %s, it can only be declared lost", instance.Status)
+		}
+	}
+
+	switch instance.Status {
+	case domain.InstanceAvailable:
+		r.cancelRequestedTransfer(instanceID, now)
+	case domain.InstanceOnLoan:
+		r.closeLostLoan(instanceID, now)
+	case domain.InstanceOnHoldShelf:
+		r.requeueHold(instanceID)
+	case domain.InstanceInTransit:
+		if transferID, ok := r.transferInTransitByInstance(instanceID); ok {
+			r.cancelTransfer(transferID, now)
+		}
+	}
+
+	var err error
+	if status == domain.InstanceAvailable {
+		err = r.shelveInstance(instanceID, now)
+	} else {
+		err = r.setInstanceStatus(instanceID, status)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = r.record(actor, "update_instance_status", "instance", instanceID, instance, r.instance[instanceID])
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -178,13 +300,15 @@ func (r *Repository) CheckAvailability(instanceID int) (bool, error) {
 	}
 
 	switch instance.Status {
-	case inLibrary:
+	case domain.InstanceAvailable:
 		return true, nil
 	default:
 		return false, nil
 	}
 }
 
+// CountPublishedBooks counts the books the author is credited on as an author
+// or an illustrator.
 func (r *Repository) CountPublishedBooks(authorID int) (int, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -197,8 +321,11 @@ func (r *Repository) CountPublishedBooks(authorID int) (int, error) {
 	var counter int
 
 	for _, book := range r.books {
-		if book.AuthorID == authorID {
-			counter++
+		for _, author := range book.Authors {
+			if author.AuthorID == authorID && domain.IsAuthoringRole(author.Role) {
+				counter++
+				break
+			}
 		}
 	}
 
@@ -209,21 +336,29 @@ func (r *Repository) CheckBorrowBooks(readerID int) ([]domain.BookMapField, erro
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	reader, ok := r.reader[readerID]
+	_, ok := r.reader[readerID]
 	if !ok {
 		return nil, fmt.Errorf("reader not found")
 	}
 
 	books := make([]domain.BookMapField, 0)
 
-	for _, instance := range reader.InstanceID {
-		for id := range r.instance {
-			if instance == id {
-				bookID := r.instance[id].BookID
-				book := r.books[bookID]
-				books = append(books, book)
-			}
+	for _, loan := range r.sortedLoans() {
+		if loan.ReaderID != readerID || !loan.IsOpen() {
+			continue
 		}
+
+		instance, ok := r.instance[loan.InstanceID]
+		if !ok {
+			continue
+		}
+
+		book, ok := r.books[instance.BookID]
+		if !ok {
+			continue
+		}
+
+		books = append(books, book)
 	}
 
 	if len(books) == 0 {
@@ -232,3 +367,306 @@ func (r *Repository) CheckBorrowBooks(readerID int) ([]domain.BookMapField, erro
 
 	return books, nil
 }
+
+func (r *Repository) GetInstanceLoan(instanceID int) (*domain.LoanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.instance[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found")
+	}
+
+	loanID, ok := r.openLoanByInstance(instanceID)
+	if !ok {
+		return nil, fmt.Errorf("instance is not on loan")
+	}
+
+	loan := r.loan[loanID]
+	return &loan, nil
+}
+
+func (r *Repository) GetReaderLoans(readerID int) ([]domain.LoanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	loans := make([]domain.LoanMapField, 0)
+
+	for _, loan := range r.sortedLoans() {
+		if loan.ReaderID == readerID {
+			loans = append(loans, loan)
+		}
+	}
+
+	return loans, nil
+}
+
+// RenewLoan pushes the due date out by the loan period. A loan renewed within
+// the grace period after it fell due is charged the fine accrued until then,
+// which the new due date would otherwise write off.
+func (r *Repository) RenewLoan(readerID, loanID int) (*domain.LoanMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	err := r.checkReader(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, ok := r.loan[loanID]
+	if !ok || loan.ReaderID != readerID {
+		return nil, fmt.Errorf("loan not found")
+	}
+
+	if !loan.IsOpen() {
+		return nil, fmt.Errorf("loan is already closed")
+	}
+
+	if len(loan.Renewals) >= loan.MaxRenewals {
+		return nil, fmt.Errorf("maximum number of renewals reached")
+	}
+
+	if now.After(loan.DueDate.Add(r.renewalGracePeriod)) {
+		return nil, fmt.Errorf("loan is overdue")
+	}
+
+	if _, ok = r.firstWaitingHold(r.instance[loan.InstanceID].BookID); ok {
+		return nil, fmt.Errorf("book has pending holds")
+	}
+
+	renewal := domain.LoanRenewal{
+		RenewDate:       now,
+		PreviousDueDate: loan.DueDate,
+		DueDate:         loan.DueDate.Add(loan.LoanPeriod),
+		Fine:            loan.Fine(now),
+	}
+
+	if renewal.Fine > 0 {
+		r.addFine(domain.FineMapField{
+			ReaderID: readerID,
+			Kind:     domain.FineCharge,
+			Amount:   renewal.Fine,
+			LoanID:   loanID,
+			Reason:   "overdue before renewal",
+			Date:     now,
+		})
+	}
+
+	loan.Renewals = append(loan.Renewals, renewal)
+	loan.DueDate = renewal.DueDate
+	put(r, r.loan, loanID, loan)
+
+	return &loan, nil
+}
+
+// takeInstance opens the loan of a checkout that passed checkoutProblems. The
+// caller must hold r.mu.
+func (r *Repository) takeInstance(readerID, instanceID int, terms domain.LoanTerms, now time.Time) (*domain.LoanMapField, error) {
+	if r.openLoansInCategory(readerID, terms.ItemCategory) >= terms.MaxLoans {
+		return nil, fmt.Errorf("loan limit %d reached", terms.MaxLoans)
+	}
+
+	instance := r.instance[instanceID]
+
+	err := r.setInstanceStatus(instanceID, domain.InstanceOnLoan)
+	if err != nil {
+		return nil, err
+	}
+
+	// checkoutProblems let the reader take the copy kept on the hold shelf
+	// for them or, from the shelf, the book they are first in line for
+	var holdID int
+	var ok bool
+	switch instance.Status {
+	case domain.InstanceOnHoldShelf:
+		holdID, ok = r.readyHoldByInstance(instanceID)
+	case domain.InstanceAvailable:
+		holdID, ok = r.firstWaitingHold(instance.BookID)
+	}
+
+	if ok && r.hold[holdID].ReaderID == readerID {
+		hold := r.hold[holdID]
+		hold.Status = domain.HoldFulfilled
+		hold.InstanceID = instanceID
+		hold.CloseDate = &now
+		put(r, r.hold, holdID, hold)
+	}
+
+	reader := r.reader[readerID]
+	reader.InstanceID = append(reader.InstanceID, instanceID)
+	put(r, r.reader, readerID, reader)
+
+	loan := r.openLoan(readerID, instanceID, now, terms)
+
+	return &loan, nil
+}
+
+// setInstanceStatus moves the instance to the status if the transition table
+// allows it. A copy on loan or in transit keeps its status until its loan or
+// transfer is closed, so the workflows close them first. The caller must hold
+// r.mu.
+func (r *Repository) setInstanceStatus(instanceID int, status domain.InstanceStatus) error {
+	instance, ok := r.instance[instanceID]
+	if !ok {
+		return fmt.Errorf("instance not found")
+	}
+
+	if !instance.Status.CanTransitionTo(status) {
+		return fmt.Errorf("illegal status transition from %s to %s", instance.Status, status)
+	}
+
+	switch instance.Status {
+	case domain.InstanceOnLoan:
+		if loanID, ok := r.openLoanByInstance(instanceID); ok {
+			return fmt.Errorf("instance is still on loan %d", loanID)
+		}
+	case domain.InstanceInTransit:
+		if transferID, ok := r.transferInTransitByInstance(instanceID); ok {
+			return fmt.Errorf("instance is still in transfer %d", transferID)
+		}
+	}
+
+	instance.Status = status
+	put(r, r.instance, instanceID, instance)
+
+	return nil
+}
+
+// closeLostLoan closes the open loan of a copy declared lost, charges the
+// fine it has accrued so far and removes it from the reader's record. The
+// caller must hold r.mu.
+func (r *Repository) closeLostLoan(instanceID int, now time.Time) {
+	loanID, ok := r.openLoanByInstance(instanceID)
+	if !ok {
+		return
+	}
+
+	loan := r.loan[loanID]
+	loan.ReturnDate = &now
+	put(r, r.loan, loanID, loan)
+	r.chargeOverdueFine(loan)
+
+	reader, ok := r.reader[loan.ReaderID]
+	if ok {
+		reader.InstanceID = removeID(reader.InstanceID, instanceID)
+		put(r, r.reader, loan.ReaderID, reader)
+	}
+}
+
+// requeueHold puts the hold a lost copy was kept for back in the queue, where
+// it keeps its place. The caller must hold r.mu.
+func (r *Repository) requeueHold(instanceID int) {
+	holdID, ok := r.readyHoldByInstance(instanceID)
+	if !ok {
+		return
+	}
+
+	hold := r.hold[holdID]
+	hold.Status = domain.HoldWaiting
+	hold.InstanceID = 0
+	hold.ReadyDate = nil
+	hold.ExpireDate = nil
+	put(r, r.hold, holdID, hold)
+}
+
+// defaultTerms are the terms of loans restored from readers.json, which were
+// opened before any lending policy applied to them. The caller must hold r.mu.
+func (r *Repository) defaultTerms(instanceID int) domain.LoanTerms {
+	return domain.LoanTerms{
+		ItemCategory: r.books[r.instance[instanceID].BookID].Category,
+		LoanPeriod:   r.loanPeriod,
+		MaxRenewals:  r.maxRenewals,
+		FineRate:     r.fineDailyRate,
+		FineCap:      r.fineCap,
+	}
+}
+
+// checkReader reports whether the reader is allowed to borrow: the reader must
+// exist and have a user account that is not banned. Who may act for the
+// reader is decided when the request is authenticated. The caller must hold
+// r.mu.
+func (r *Repository) checkReader(readerID int) error {
+	_, ok := r.reader[readerID]
+	if !ok {
+		return fmt.Errorf("reader not found")
+	}
+
+	r.expireBans(time.Now())
+
+	user, ok := r.user[readerID]
+	if !ok {
+		return fmt.Errorf("reader has no user account")
+	}
+
+	if user.Banned {
+		return fmt.Errorf("reader is banned")
+	}
+
+	return nil
+}
+
+// openLoan records a new loan of the instance to the reader. The caller must
+// hold r.mu.
+func (r *Repository) openLoan(readerID, instanceID int, checkoutDate time.Time, terms domain.LoanTerms) domain.LoanMapField {
+	r.loanSeq++
+	loan := domain.LoanMapField{
+		LoanID:       r.loanSeq,
+		ReaderID:     readerID,
+		InstanceID:   instanceID,
+		ItemCategory: terms.ItemCategory,
+		CheckoutDate: checkoutDate,
+		DueDate:      checkoutDate.Add(terms.LoanPeriod),
+		FineRate:     terms.FineRate,
+		FineCap:      terms.FineCap,
+		LoanPeriod:   terms.LoanPeriod,
+		MaxRenewals:  terms.MaxRenewals,
+	}
+	put(r, r.loan, loan.LoanID, loan)
+
+	return loan
+}
+
+// openLoanByInstance returns the id of the open loan for the instance. The
+// caller must hold r.mu.
+func (r *Repository) openLoanByInstance(instanceID int) (int, bool) {
+	for loanID, loan := range r.loan {
+		if loan.InstanceID == instanceID && loan.IsOpen() {
+			return loanID, true
+		}
+	}
+
+	return 0, false
+}
+
+// sortedLoans returns every loan ordered by id. The caller must hold r.mu.
+func (r *Repository) sortedLoans() []domain.LoanMapField {
+	loans := make([]domain.LoanMapField, 0, len(r.loan))
+	for _, loan := range r.loan {
+		loans = append(loans, loan)
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].LoanID < loans[j].LoanID
+	})
+
+	return loans
+}
+
+func removeID(ids []int, id int) []int {
+	result := make([]int, 0, len(ids))
+	for _, v := range ids {
+		if v != id {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
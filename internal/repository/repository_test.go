@@ -0,0 +1,334 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"testing"
+	"time"
+)
+
+const (
+	testAdminID  = 1
+	testReaderID = 2
+	testOtherID  = 3
+	testThirdID  = 4
+
+	testBookID = 1
+
+	testHomeBranchID  = 1
+	testOtherBranchID = 2
+
+	testFineRate = 10
+	testFineCap  = 100
+)
+
+var testAdmin = domain.Actor{
+	Principal: domain.Principal{UserID: testAdminID, Roles: []string{domain.RoleAdmin}},
+	RequestID: "test",
+}
+
+var testTerms = domain.LoanTerms{
+	ReaderCategory: defaultCategory,
+	ItemCategory:   defaultCategory,
+	MaxLoans:       5,
+	LoanPeriod:     14 * 24 * time.Hour,
+	MaxRenewals:    2,
+	FineRate:       testFineRate,
+	FineCap:        testFineCap,
+}
+
+// newTestRepository returns a repository with an admin, three readers, two
+// branches and one book without copies, with its author, genre, production
+// and language.
+func newTestRepository(t *testing.T, opts ...Option) *Repository {
+	t.Helper()
+
+	r, err := New(opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	r.admins[testAdminID] = domain.AdminMapField{}
+	r.user[testAdminID] = domain.UserMapField{Name: "admin"}
+	for _, readerID := range []int{testReaderID, testOtherID, testThirdID} {
+		r.user[readerID] = domain.UserMapField{Name: "reader"}
+		r.reader[readerID] = domain.ReaderMapField{InstanceID: make([]int, 0), Category: defaultCategory}
+	}
+
+	r.branch[testHomeBranchID] = domain.BranchMapField{Name: "home"}
+	r.branch[testOtherBranchID] = domain.BranchMapField{Name: "other"}
+	r.defaultBranchID = testHomeBranchID
+
+	r.author[1] = domain.AuthorMapField{Name: "Lev", Surname: "Tolstoy", ProductionID: 1}
+	r.genres[1] = domain.GenreMapField{Name: "novel"}
+	r.production[1] = domain.ProductionMapField{Name: "Penguin"}
+	r.language[1] = domain.LanguageMapField{Name: "english"}
+	r.authorSeq, r.genreSeq, r.productionSeq, r.languageSeq = 1, 1, 1, 1
+
+	r.books[testBookID] = newTestBook("War and Peace")
+	r.bookSeq = testBookID
+	r.indexBook(testBookID)
+
+	return r
+}
+
+// addInstance adds a copy of the test book in the status at the branch,
+// whose home is the home branch.
+func addInstance(r *Repository, status domain.InstanceStatus, branchID int) int {
+	r.instanceSeq++
+	r.instance[r.instanceSeq] = domain.InstanceMapField{
+		BookID:          testBookID,
+		Status:          status,
+		HomeBranchID:    testHomeBranchID,
+		CurrentBranchID: branchID,
+	}
+
+	return r.instanceSeq
+}
+
+// lend checks a copy of the test book out to the reader, due on dueDate.
+func lend(t *testing.T, r *Repository, readerID int, dueDate time.Time) (int, int) {
+	t.Helper()
+
+	instanceID := addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+	_, err := r.TakeBook(readerID, instanceID, testTerms)
+	if err != nil {
+		t.Fatalf("TakeBook: %v", err)
+	}
+
+	loanID, _ := r.openLoanByInstance(instanceID)
+	loan := r.loan[loanID]
+	loan.CheckoutDate = dueDate.Add(-testTerms.LoanPeriod)
+	loan.DueDate = dueDate
+	r.loan[loanID] = loan
+
+	return instanceID, loanID
+}
+
+// newTestBook returns a book by the author of the test book.
+func newTestBook(name string) domain.BookMapField {
+	return domain.BookMapField{
+		Name:         name,
+		Authors:      []domain.BookAuthor{{AuthorID: 1, Role: domain.AuthorRoleAuthor}},
+		GenreIDs:     []int{1},
+		ProductionID: 1,
+		LanguageID:   1,
+		Category:     defaultCategory,
+	}
+}
+
+// holdShelf puts a copy of the test book on the hold shelf for the reader:
+// the other reader returns it while the reader holds the book.
+func holdShelf(t *testing.T, r *Repository, readerID int) (int, int) {
+	t.Helper()
+
+	otherID := testOtherID
+	if readerID == testOtherID {
+		otherID = testReaderID
+	}
+
+	instanceID, _ := lend(t, r, otherID, time.Now().Add(time.Hour))
+	hold, err := r.PlaceHold(readerID, testBookID)
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+
+	if err = r.ReturnBook(otherID, instanceID); err != nil {
+		t.Fatalf("ReturnBook: %v", err)
+	}
+
+	return instanceID, hold.HoldID
+}
+
+// dispatch sends a copy of the test book from the home branch to the other
+// one for the reader.
+func dispatch(t *testing.T, r *Repository, readerID int) (int, int) {
+	t.Helper()
+
+	instanceID := addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+	transfer, err := r.RequestTransfer(readerID, testBookID, testOtherBranchID)
+	if err != nil {
+		t.Fatalf("RequestTransfer: %v", err)
+	}
+
+	if _, err = r.DispatchTransfer(testAdmin, transfer.TransferID); err != nil {
+		t.Fatalf("DispatchTransfer: %v", err)
+	}
+
+	return instanceID, transfer.TransferID
+}
+
+func TestUpdateInstanceStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, r *Repository) int
+		status  domain.InstanceStatus
+		wantErr bool
+		check   func(t *testing.T, r *Repository, instanceID int)
+	}{
+		{
+			name: "available to repair",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			},
+			status: domain.InstanceInRepair,
+		},
+		{
+			name: "repair to withdrawn",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceInRepair, testHomeBranchID)
+			},
+			status: domain.InstanceWithdrawn,
+		},
+		{
+			name: "lost to repair",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceLost, testHomeBranchID)
+			},
+			status:  domain.InstanceInRepair,
+			wantErr: true,
+		},
+		{
+			name: "same status",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceInRepair, testHomeBranchID)
+			},
+			status:  domain.InstanceInRepair,
+			wantErr: true,
+		},
+		{
+			name: "available to on loan",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			},
+			status:  domain.InstanceOnLoan,
+			wantErr: true,
+		},
+		{
+			name: "available to hold shelf",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			},
+			status:  domain.InstanceOnHoldShelf,
+			wantErr: true,
+		},
+		{
+			name: "available to in transit",
+			prepare: func(t *testing.T, r *Repository) int {
+				return addInstance(r, domain.InstanceAvailable, testHomeBranchID)
+			},
+			status:  domain.InstanceInTransit,
+			wantErr: true,
+		},
+		{
+			name: "on loan to available",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := lend(t, r, testReaderID, time.Now().Add(time.Hour))
+				return instanceID
+			},
+			status:  domain.InstanceAvailable,
+			wantErr: true,
+		},
+		{
+			name: "on loan to lost",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := lend(t, r, testReaderID, time.Now().Add(-36*time.Hour))
+				return instanceID
+			},
+			status: domain.InstanceLost,
+			check: func(t *testing.T, r *Repository, instanceID int) {
+				if _, ok := r.openLoanByInstance(instanceID); ok {
+					t.Error("loan is still open")
+				}
+
+				if len(r.reader[testReaderID].InstanceID) != 0 {
+					t.Errorf("reader still holds %v", r.reader[testReaderID].InstanceID)
+				}
+
+				if account := r.fineAccount(testReaderID, time.Now()); account.Charged != 2*testFineRate {
+					t.Errorf("charged %d, want %d", account.Charged, 2*testFineRate)
+				}
+			},
+		},
+		{
+			name: "hold shelf to available",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := holdShelf(t, r, testReaderID)
+				return instanceID
+			},
+			status:  domain.InstanceAvailable,
+			wantErr: true,
+		},
+		{
+			name: "hold shelf to lost",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := holdShelf(t, r, testReaderID)
+				return instanceID
+			},
+			status: domain.InstanceLost,
+			check: func(t *testing.T, r *Repository, instanceID int) {
+				if hold := r.hold[1]; hold.Status != domain.HoldWaiting || hold.InstanceID != 0 {
+					t.Errorf("hold = %+v, want it waiting again", hold)
+				}
+			},
+		},
+		{
+			name: "in transit to available",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := dispatch(t, r, testReaderID)
+				return instanceID
+			},
+			status:  domain.InstanceAvailable,
+			wantErr: true,
+		},
+		{
+			name: "in transit to lost",
+			prepare: func(t *testing.T, r *Repository) int {
+				instanceID, _ := dispatch(t, r, testReaderID)
+				return instanceID
+			},
+			status: domain.InstanceLost,
+			check: func(t *testing.T, r *Repository, instanceID int) {
+				if transfer := r.transfer[1]; transfer.Status != domain.TransferCancelled {
+					t.Errorf("transfer is %s, want %s", transfer.Status, domain.TransferCancelled)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t)
+			instanceID := tt.prepare(t, r)
+			previous := r.instance[instanceID].Status
+
+			err := r.UpdateInstanceStatus(testAdmin, instanceID, tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateInstanceStatus(%s to %s) = %v, want error %t", previous, tt.status, err, tt.wantErr)
+			}
+
+			want := tt.status
+			if tt.wantErr {
+				want = previous
+			}
+
+			if got := r.instance[instanceID].Status; got != want {
+				t.Errorf("status = %s, want %s", got, want)
+			}
+
+			if tt.check != nil {
+				tt.check(t, r, instanceID)
+			}
+		})
+	}
+}
+
+func TestSetInstanceStatusKeepsOpenLoan(t *testing.T) {
+	r := newTestRepository(t)
+	instanceID, _ := lend(t, r, testReaderID, time.Now().Add(time.Hour))
+
+	for _, status := range []domain.InstanceStatus{domain.InstanceAvailable, domain.InstanceOnHoldShelf} {
+		if err := r.setInstanceStatus(instanceID, status); err == nil {
+			t.Errorf("setInstanceStatus(%s) left loan open", status)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	search "book-inventory-system/pkg/search"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	searchFieldName        = "name"
+	searchFieldAuthors     = "authors"
+	searchFieldDescription = "description"
+)
+
+// searchWeights ranks a match in the name above a match in the author names,
+// and both above a match in the description.
+var searchWeights = map[string]float64{
+	searchFieldName:        3,
+	searchFieldAuthors:     2,
+	searchFieldDescription: 1,
+}
+
+// Search returns at most limit books matching the query, best match first.
+func (r *Repository) Search(query string, limit int) ([]domain.SearchResult, error) {
+	if len(search.Tokenize(query)) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid limit: %d", limit)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	found := r.search.Search(query)
+	if len(found) > limit {
+		found = found[:limit]
+	}
+
+	results := make([]domain.SearchResult, 0, len(found))
+	for _, result := range found {
+		results = append(results, domain.SearchResult{
+			BookEntry: domain.BookEntry{
+				BookID:       result.DocID,
+				BookMapField: r.books[result.DocID],
+			},
+			Score:        result.Score,
+			Availability: r.bookAvailability(result.DocID, 0),
+		})
+	}
+
+	return results, nil
+}
+
+// indexBook adds the book to the search index or refreshes it. The caller
+// must hold r.mu.
+func (r *Repository) indexBook(bookID int) {
+	if r.journal != nil {
+		r.journal.reindex[bookID] = struct{}{}
+	}
+
+	book, ok := r.books[bookID]
+	if !ok || book.Archived {
+		r.search.Remove(bookID)
+		return
+	}
+
+	authors := make([]string, 0, len(book.Authors))
+	for _, bookAuthor := range book.Authors {
+		author, ok := r.author[bookAuthor.AuthorID]
+		if !ok {
+			continue
+		}
+
+		authors = append(authors, author.Name, author.Surname, author.Patronymic)
+	}
+
+	r.search.Add(bookID, map[string]string{
+		searchFieldName:        book.Name,
+		searchFieldAuthors:     strings.Join(authors, " "),
+		searchFieldDescription: book.Description,
+	})
+}
@@ -0,0 +1,138 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// GetSeries lists the volumes of the series in reading order with their
+// availability at the branch, or in the whole library for branch 0.
+func (r *Repository) GetSeries(seriesID, branchID int) (*domain.SeriesListing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	series, ok := r.series[seriesID]
+	if !ok {
+		return nil, fmt.Errorf("series not found")
+	}
+
+	if branchID != 0 {
+		if _, ok = r.branch[branchID]; !ok {
+			return nil, fmt.Errorf("branch not found")
+		}
+	}
+
+	listing := domain.SeriesListing{
+		SeriesID:    seriesID,
+		Name:        series.Name,
+		Description: series.Description,
+		Volumes:     make([]domain.SeriesVolume, 0),
+	}
+
+	for _, bookID := range r.seriesBooks(seriesID) {
+		book := r.books[bookID]
+		listing.Volumes = append(listing.Volumes, domain.SeriesVolume{
+			Volume:       book.Volume,
+			BookID:       bookID,
+			Name:         book.Name,
+			Availability: r.bookAvailability(bookID, branchID),
+		})
+	}
+
+	return &listing, nil
+}
+
+// NextInSeries suggests, for every series the reader has borrowed from, the
+// first unread volume after the last one they borrowed that has an available
+// copy. Series with no such volume are left out.
+func (r *Repository) NextInSeries(readerID int) ([]domain.SeriesSuggestion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expireHolds(time.Now())
+
+	_, ok := r.reader[readerID]
+	if !ok {
+		return nil, fmt.Errorf("reader not found")
+	}
+
+	read := make(map[int]struct{})
+	lastRead := make(map[int]int)
+	for _, loan := range r.loan {
+		if loan.ReaderID != readerID {
+			continue
+		}
+
+		bookID := r.instance[loan.InstanceID].BookID
+		read[bookID] = struct{}{}
+
+		book, ok := r.books[bookID]
+		if !ok || book.SeriesID == 0 {
+			continue
+		}
+
+		if book.Volume > lastRead[book.SeriesID] {
+			lastRead[book.SeriesID] = book.Volume
+		}
+	}
+
+	suggestions := make([]domain.SeriesSuggestion, 0, len(lastRead))
+	for seriesID, lastVolume := range lastRead {
+		for _, bookID := range r.seriesBooks(seriesID) {
+			book := r.books[bookID]
+			if book.Volume <= lastVolume {
+				continue
+			}
+
+			if _, ok = read[bookID]; ok {
+				continue
+			}
+
+			if r.bookAvailability(bookID, 0).Available == 0 {
+				continue
+			}
+
+			suggestions = append(suggestions, domain.SeriesSuggestion{
+				SeriesID:       seriesID,
+				SeriesName:     r.series[seriesID].Name,
+				LastReadVolume: lastVolume,
+				Volume:         book.Volume,
+				BookID:         bookID,
+				Name:           book.Name,
+			})
+			break
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].SeriesID < suggestions[j].SeriesID
+	})
+
+	return suggestions, nil
+}
+
+// seriesBooks returns the ids of the books of the series ordered by volume.
+// The caller must hold r.mu.
+func (r *Repository) seriesBooks(seriesID int) []int {
+	bookIDs := make([]int, 0)
+	for bookID, book := range r.books {
+		if book.SeriesID == seriesID {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	sort.Slice(bookIDs, func(i, j int) bool {
+		left, right := r.books[bookIDs[i]], r.books[bookIDs[j]]
+		if left.Volume != right.Volume {
+			return left.Volume < right.Volume
+		}
+
+		return bookIDs[i] < bookIDs[j]
+	})
+
+	return bookIDs
+}
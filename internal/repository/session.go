@@ -0,0 +1,250 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Login checks the name and password of a user and opens a session. Unknown
+// names and wrong passwords are told apart neither by the error nor by the
+// time it takes.
+func (r *Repository) Login(name, password string) (*domain.Login, error) {
+	r.mu.Lock()
+	userID, hash, ok := r.userByName(name)
+	r.mu.Unlock()
+
+	// the password is compared without holding r.mu, as in VerifyPassword
+	if !ok {
+		comparePassword(dummyPasswordHash, password)
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if !comparePassword(hash, password) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireBans(now)
+	r.expireSessions(now)
+
+	user, ok := r.user[userID]
+	if !ok || user.Password != hash {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if user.Banned {
+		return nil, fmt.Errorf("user is banned")
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	r.sessionSeq++
+	session := domain.SessionMapField{
+		SessionID:      r.sessionSeq,
+		UserID:         userID,
+		CreateDate:     now,
+		LastActiveDate: now,
+		IdleExpireDate: now.Add(r.sessionIdleTimeout),
+		ExpireDate:     now.Add(r.sessionTTL),
+	}
+	put(r, r.session, sessionKey(token), session)
+	r.refreshLoginStatus(userID, now)
+
+	return &domain.Login{
+		Token:   token,
+		Session: session,
+	}, nil
+}
+
+// Logout ends the session of the token.
+func (r *Repository) Logout(token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireSessions(now)
+
+	key := sessionKey(token)
+	session, ok := r.session[key]
+	if !ok {
+		return fmt.Errorf("session not found")
+	}
+
+	remove(r, r.session, key)
+	r.refreshLoginStatus(session.UserID, now)
+
+	return nil
+}
+
+// Authenticate returns the active session of the token and marks it as used
+// now, which postpones its idle expiry.
+func (r *Repository) Authenticate(token string) (*domain.SessionMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.expireSessions(now)
+
+	key := sessionKey(token)
+	session, ok := r.session[key]
+	if !ok {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	session.LastActiveDate = now
+	session.IdleExpireDate = now.Add(r.sessionIdleTimeout)
+	put(r, r.session, key, session)
+
+	return &session, nil
+}
+
+// GetUserSessions returns the active sessions of the user, oldest first.
+func (r *Repository) GetUserSessions(principal domain.Principal, userID int) ([]domain.SessionMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[principal.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.expireSessions(time.Now())
+
+	_, ok = r.user[userID]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	sessions := make([]domain.SessionMapField, 0)
+	for _, session := range r.session {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].SessionID < sessions[j].SessionID
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession ends a session of any user.
+func (r *Repository) RevokeSession(actor domain.Actor, sessionID int) (*domain.SessionMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	now := time.Now()
+	r.expireSessions(now)
+
+	for key, session := range r.session {
+		if session.SessionID != sessionID {
+			continue
+		}
+
+		remove(r, r.session, key)
+		r.refreshLoginStatus(session.UserID, now)
+
+		err := r.record(actor, "revoke_session", "user", session.UserID, session, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &session, nil
+	}
+
+	return nil, fmt.Errorf("session not found")
+}
+
+// userByName returns the id and the password hash of the user with the name.
+// The caller must hold r.mu.
+func (r *Repository) userByName(name string) (int, string, bool) {
+	for userID, user := range r.user {
+		if user.Name == name {
+			return userID, user.Password, true
+		}
+	}
+
+	return 0, "", false
+}
+
+// endUserSessions ends every session of the user. The caller must hold r.mu.
+func (r *Repository) endUserSessions(userID int, now time.Time) {
+	for key, session := range r.session {
+		if session.UserID == userID {
+			remove(r, r.session, key)
+		}
+	}
+
+	r.refreshLoginStatus(userID, now)
+}
+
+// expireSessions ends the sessions that have expired or been idle for too
+// long. The caller must hold r.mu.
+func (r *Repository) expireSessions(now time.Time) {
+	for key, session := range r.session {
+		if session.IsActive(now) {
+			continue
+		}
+
+		remove(r, r.session, key)
+		r.refreshLoginStatus(session.UserID, now)
+	}
+}
+
+// refreshLoginStatus derives the login status of the user from the sessions:
+// a user is logged in while any session of theirs is active. The caller must
+// hold r.mu.
+func (r *Repository) refreshLoginStatus(userID int, now time.Time) {
+	user, ok := r.user[userID]
+	if !ok {
+		return
+	}
+
+	user.LoginStatus = loggedOut
+	for _, session := range r.session {
+		if session.UserID == userID && session.IsActive(now) {
+			user.LoginStatus = loggedIn
+			break
+		}
+	}
+
+	put(r, r.user, userID, user)
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sessionKey is what sessions are stored under, so that the tokens
+// themselves are never kept.
+func sessionKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,211 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OpenStocktake opens a session for the shelves of one branch. Each branch
+// has at most one open session.
+func (r *Repository) OpenStocktake(actor domain.Actor, branchID int) (*domain.StocktakeMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	_, ok = r.branch[branchID]
+	if !ok {
+		return nil, fmt.Errorf("branch not found")
+	}
+
+	for _, stocktake := range r.stocktake {
+		if stocktake.Status == domain.StocktakeOpen && stocktake.BranchID == branchID {
+			return nil, fmt.Errorf("stocktake %d is already open at the branch", stocktake.StocktakeID)
+		}
+	}
+
+	r.stocktakeSeq++
+	stocktake := domain.StocktakeMapField{
+		StocktakeID: r.stocktakeSeq,
+		AdminID:     actor.UserID,
+		BranchID:    branchID,
+		Status:      domain.StocktakeOpen,
+		OpenDate:    time.Now(),
+		Scanned:     make([]int, 0),
+	}
+	put(r, r.stocktake, stocktake.StocktakeID, stocktake)
+
+	err := r.record(actor, "open_stocktake", "stocktake", stocktake.StocktakeID, nil, stocktake)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stocktake, nil
+}
+
+// ScanStocktake adds the scanned instance ids to the open session. Ids that
+// were already scanned are ignored.
+func (r *Repository) ScanStocktake(stocktakeID int, instanceIDs []int) (*domain.StocktakeMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktake[stocktakeID]
+	if !ok {
+		return nil, fmt.Errorf("stocktake not found")
+	}
+
+	if stocktake.Status != domain.StocktakeOpen {
+		return nil, fmt.Errorf("stocktake is closed")
+	}
+
+	scanned := make(map[int]struct{}, len(stocktake.Scanned))
+	for _, instanceID := range stocktake.Scanned {
+		scanned[instanceID] = struct{}{}
+	}
+
+	for _, instanceID := range instanceIDs {
+		if _, ok = scanned[instanceID]; ok {
+			continue
+		}
+
+		scanned[instanceID] = struct{}{}
+		stocktake.Scanned = append(stocktake.Scanned, instanceID)
+	}
+
+	put(r, r.stocktake, stocktakeID, stocktake)
+
+	return &stocktake, nil
+}
+
+// CloseStocktake closes the session and reconciles it with the recorded
+// statuses. With markLost the missing copies are declared lost, which puts
+// their holds back in the queue and cancels the transfers they were reserved
+// for.
+func (r *Repository) CloseStocktake(actor domain.Actor, stocktakeID int, markLost bool) (*domain.StocktakeMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.admins[actor.UserID]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	r.begin()
+	defer r.rollback()
+
+	stocktake, ok := r.stocktake[stocktakeID]
+	if !ok {
+		return nil, fmt.Errorf("stocktake not found")
+	}
+
+	if stocktake.Status != domain.StocktakeOpen {
+		return nil, fmt.Errorf("stocktake is closed")
+	}
+
+	previous := stocktake
+
+	now := time.Now()
+	r.expireHolds(now)
+
+	report := r.reconcileStocktake(stocktake.BranchID, stocktake.Scanned)
+
+	if markLost {
+		for _, instanceID := range report.Missing {
+			wasOnHoldShelf := r.instance[instanceID].Status == domain.InstanceOnHoldShelf
+
+			err := r.setInstanceStatus(instanceID, domain.InstanceLost)
+			if err != nil {
+				return nil, err
+			}
+
+			if wasOnHoldShelf {
+				r.requeueHold(instanceID)
+			}
+
+			r.cancelRequestedTransfer(instanceID, now)
+
+			report.MarkedLost = append(report.MarkedLost, instanceID)
+		}
+	}
+
+	stocktake.Status = domain.StocktakeClosed
+	stocktake.CloseDate = &now
+	stocktake.Report = &report
+	put(r, r.stocktake, stocktakeID, stocktake)
+
+	err := r.record(actor, "close_stocktake", "stocktake", stocktakeID, previous, stocktake)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stocktake, nil
+}
+
+func (r *Repository) GetStocktake(stocktakeID int) (*domain.StocktakeMapField, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktake[stocktakeID]
+	if !ok {
+		return nil, fmt.Errorf("stocktake not found")
+	}
+
+	return &stocktake, nil
+}
+
+// reconcileStocktake compares the scanned ids with the copies r.instance
+// records at the branch. The caller must hold r.mu.
+func (r *Repository) reconcileStocktake(branchID int, scannedIDs []int) domain.StocktakeReport {
+	report := domain.StocktakeReport{
+		Missing:   make([]int, 0),
+		OnLoan:    make([]int, 0),
+		Misplaced: make([]int, 0),
+		Unknown:   make([]int, 0),
+	}
+
+	scanned := make(map[int]struct{}, len(scannedIDs))
+	for _, instanceID := range scannedIDs {
+		scanned[instanceID] = struct{}{}
+
+		instance, ok := r.instance[instanceID]
+		switch {
+		case !ok:
+			report.Unknown = append(report.Unknown, instanceID)
+		case instance.Status == domain.InstanceOnLoan:
+			report.OnLoan = append(report.OnLoan, instanceID)
+		case instance.CurrentBranchID != branchID:
+			report.Misplaced = append(report.Misplaced, instanceID)
+		default:
+			report.Found++
+		}
+	}
+
+	for instanceID, instance := range r.instance {
+		if instance.CurrentBranchID != branchID {
+			continue
+		}
+
+		if instance.Status != domain.InstanceAvailable && instance.Status != domain.InstanceOnHoldShelf {
+			continue
+		}
+
+		if _, ok := scanned[instanceID]; !ok {
+			report.Missing = append(report.Missing, instanceID)
+		}
+	}
+
+	sort.Ints(report.Missing)
+	sort.Ints(report.OnLoan)
+	sort.Ints(report.Misplaced)
+	sort.Ints(report.Unknown)
+
+	return report
+}
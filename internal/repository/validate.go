@@ -0,0 +1,342 @@
+package book_inventory_system_repository
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"strings"
+)
+
+const (
+	adminsFile      = "admins.json"
+	authorsFile     = "authors.json"
+	booksFile       = "books.json"
+	genresFile      = "genres.json"
+	instancesFile   = "instances.json"
+	languagesFile   = "languages.json"
+	productionsFile = "productions.json"
+	readersFile     = "readers.json"
+	usersFile       = "users.json"
+	branchesFile    = "branches.json"
+	seriesFile      = "series.json"
+)
+
+// Violations returns the integrity violations found while loading the dumps,
+// in the order they were found.
+func (r *Repository) Violations() []domain.Violation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	violations := make([]domain.Violation, len(r.violations))
+	copy(violations, r.violations)
+
+	return violations
+}
+
+// addViolation records a violation. The caller must hold r.mu or be loading
+// the dumps.
+func (r *Repository) addViolation(file string, record, id int, format string, args ...any) {
+	r.violations = append(r.violations, domain.Violation{
+		File:    file,
+		Record:  record + 1,
+		ID:      id,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// rejectedRecords are the records of each dump file that are reported and
+// not loaded.
+type rejectedRecords map[string]map[int]struct{}
+
+func (r rejectedRecords) add(file string, record int) {
+	if r[file] == nil {
+		r[file] = make(map[int]struct{})
+	}
+
+	r[file][record] = struct{}{}
+}
+
+func (r rejectedRecords) has(file string, record int) bool {
+	_, ok := r[file][record]
+	return ok
+}
+
+// idChecker rejects records of a dump file whose id is missing, zero or
+// used by an earlier record of the file.
+type idChecker struct {
+	r        *Repository
+	rejected rejectedRecords
+	file     string
+	name     string
+	seen     map[int]int
+}
+
+func (r *Repository) newIDChecker(rejected rejectedRecords, file, name string) *idChecker {
+	return &idChecker{
+		r:        r,
+		rejected: rejected,
+		file:     file,
+		name:     name,
+		seen:     make(map[int]int),
+	}
+}
+
+// check reports whether the id of the record is valid and unique.
+func (c *idChecker) check(record, id int) bool {
+	if id <= 0 {
+		c.r.addViolation(c.file, record, id, "missing or invalid %s, the record is not loaded", c.name)
+		c.rejected.add(c.file, record)
+		return false
+	}
+
+	if first, ok := c.seen[id]; ok {
+		c.r.addViolation(c.file, record, id, "duplicate %s %d, first used by record %d, the record is not loaded", c.name, id, first+1)
+		c.rejected.add(c.file, record)
+		return false
+	}
+
+	c.seen[id] = record
+	return true
+}
+
+// checkDumpIDs reports invalid and duplicate ids and missing names in the
+// dumps loaded by WithDump. It runs before anything is loaded and returns the
+// records to leave out.
+func (r *Repository) checkDumpIDs(
+	admins *domain.Admin,
+	authors *domain.Author,
+	books *domain.Book,
+	genres *domain.Genre,
+	instances *domain.Instance,
+	languages *domain.Language,
+	productions *domain.Production,
+	readers *domain.Reader,
+	users *domain.User,
+) rejectedRecords {
+	rejected := make(rejectedRecords)
+
+	checker := r.newIDChecker(rejected, adminsFile, "admin_id")
+	for record, admin := range admins.Admins {
+		checker.check(record, admin.AdminID)
+	}
+
+	checker = r.newIDChecker(rejected, authorsFile, "author_id")
+	for record, author := range authors.Authors {
+		checker.check(record, author.AuthorID)
+		if strings.TrimSpace(author.Name) == "" || strings.TrimSpace(author.Surname) == "" {
+			r.addViolation(authorsFile, record, author.AuthorID, "missing name or surname")
+		}
+	}
+
+	checker = r.newIDChecker(rejected, booksFile, "book_id")
+	for record, book := range books.Books {
+		checker.check(record, book.BookID)
+		if strings.TrimSpace(book.Name) == "" {
+			r.addViolation(booksFile, record, book.BookID, "missing name")
+		}
+	}
+
+	checker = r.newIDChecker(rejected, genresFile, "genre_id")
+	for record, genre := range genres.Genres {
+		checker.check(record, genre.GenreID)
+		if strings.TrimSpace(genre.Name) == "" {
+			r.addViolation(genresFile, record, genre.GenreID, "missing name")
+		}
+	}
+
+	checker = r.newIDChecker(rejected, instancesFile, "instance_id")
+	for record, instance := range instances.Instances {
+		checker.check(record, instance.InstanceID)
+	}
+
+	checker = r.newIDChecker(rejected, languagesFile, "language_id")
+	for record, language := range languages.Languages {
+		checker.check(record, language.LanguageID)
+		if strings.TrimSpace(language.Name) == "" {
+			r.addViolation(languagesFile, record, language.LanguageID, "missing name")
+		}
+	}
+
+	checker = r.newIDChecker(rejected, productionsFile, "production_id")
+	for record, production := range productions.Productions {
+		checker.check(record, production.ProductionID)
+		if strings.TrimSpace(production.Name) == "" {
+			r.addViolation(productionsFile, record, production.ProductionID, "missing name")
+		}
+	}
+
+	checker = r.newIDChecker(rejected, readersFile, "reader_id")
+	for record, reader := range readers.Readers {
+		checker.check(record, reader.ReaderID)
+	}
+
+	// users log in by name, so a user without a name or with the name of an
+	// earlier user is left out as well
+	names := make(map[string]int)
+	checker = r.newIDChecker(rejected, usersFile, "user_id")
+	for record, user := range users.Users {
+		if !checker.check(record, user.UserID) {
+			continue
+		}
+
+		if strings.TrimSpace(user.Name) == "" {
+			r.addViolation(usersFile, record, user.UserID, "missing name, the record is not loaded")
+			rejected.add(usersFile, record)
+			continue
+		}
+
+		if first, ok := names[user.Name]; ok {
+			r.addViolation(usersFile, record, user.UserID, "duplicate name %q, first used by record %d, the record is not loaded", user.Name, first+1)
+			rejected.add(usersFile, record)
+			continue
+		}
+
+		names[user.Name] = record
+	}
+
+	return rejected
+}
+
+// checkDumpReferences reports references of the dumps loaded by WithDump to
+// records that do not exist. It runs once everything is loaded and skips the
+// rejected records, which were not.
+func (r *Repository) checkDumpReferences(
+	rejected rejectedRecords,
+	admins *domain.Admin,
+	authors *domain.Author,
+	books *domain.Book,
+	instances *domain.Instance,
+	readers *domain.Reader,
+) {
+	for record, admin := range admins.Admins {
+		if rejected.has(adminsFile, record) {
+			continue
+		}
+
+		if _, ok := r.user[admin.AdminID]; !ok {
+			r.addViolation(adminsFile, record, admin.AdminID, "admin has no user account")
+		}
+	}
+
+	for record, author := range authors.Authors {
+		if rejected.has(authorsFile, record) {
+			continue
+		}
+
+		if _, ok := r.production[author.ProductionID]; author.ProductionID != 0 && !ok {
+			r.addViolation(authorsFile, record, author.AuthorID, "production %d not found", author.ProductionID)
+		}
+	}
+
+	for record, book := range books.Books {
+		if rejected.has(booksFile, record) {
+			continue
+		}
+
+		authorIDs := make([]int, 0, len(book.Authors)+1)
+		if len(book.Authors) == 0 && book.AuthorID != 0 {
+			authorIDs = append(authorIDs, book.AuthorID)
+		}
+
+		for _, author := range book.Authors {
+			authorIDs = append(authorIDs, author.AuthorID)
+		}
+
+		if len(authorIDs) == 0 {
+			r.addViolation(booksFile, record, book.BookID, "book has no authors")
+		}
+
+		for _, authorID := range authorIDs {
+			if _, ok := r.author[authorID]; !ok {
+				r.addViolation(booksFile, record, book.BookID, "author %d not found", authorID)
+			}
+		}
+
+		genreIDs := book.GenreIDs
+		if len(genreIDs) == 0 && book.GenreID != 0 {
+			genreIDs = []int{book.GenreID}
+		}
+
+		if len(genreIDs) == 0 {
+			r.addViolation(booksFile, record, book.BookID, "book has no genres")
+		}
+
+		for _, genreID := range genreIDs {
+			if _, ok := r.genres[genreID]; !ok {
+				r.addViolation(booksFile, record, book.BookID, "genre %d not found", genreID)
+			}
+		}
+
+		if _, ok := r.language[book.LanguageID]; !ok {
+			r.addViolation(booksFile, record, book.BookID, "language %d not found", book.LanguageID)
+		}
+
+		if _, ok := r.production[book.ProductionID]; !ok {
+			r.addViolation(booksFile, record, book.BookID, "production %d not found", book.ProductionID)
+		}
+	}
+
+	for record, instance := range instances.Instances {
+		if rejected.has(instancesFile, record) {
+			continue
+		}
+
+		if _, ok := r.books[instance.BookID]; !ok {
+			r.addViolation(instancesFile, record, instance.InstanceID, "book %d not found", instance.BookID)
+		}
+
+		for _, branchID := range []int{instance.HomeBranchID, instance.CurrentBranchID} {
+			if _, ok := r.branch[branchID]; branchID != 0 && !ok {
+				r.addViolation(instancesFile, record, instance.InstanceID, "branch %d not found", branchID)
+			}
+		}
+	}
+
+	holders := make(map[int]int)
+	for record, reader := range readers.Readers {
+		if rejected.has(readersFile, record) {
+			continue
+		}
+
+		if _, ok := r.user[reader.ReaderID]; !ok {
+			r.addViolation(readersFile, record, reader.ReaderID, "reader has no user account")
+		}
+
+		for _, instanceID := range reader.InstanceID {
+			instance, ok := r.instance[instanceID]
+			if !ok {
+				r.addViolation(readersFile, record, reader.ReaderID, "instance %d not found", instanceID)
+				continue
+			}
+
+			if instance.Status != domain.InstanceOnLoan {
+				r.addViolation(readersFile, record, reader.ReaderID, "instance %d is %s, not on loan", instanceID, instance.Status)
+			}
+
+			if holder, ok := holders[instanceID]; ok && holder != reader.ReaderID {
+				r.addViolation(readersFile, record, reader.ReaderID, "instance %d is also held by reader %d", instanceID, holder)
+				continue
+			}
+
+			holders[instanceID] = reader.ReaderID
+		}
+	}
+
+	for record, instance := range instances.Instances {
+		if rejected.has(instancesFile, record) {
+			continue
+		}
+
+		loaded := r.instance[instance.InstanceID]
+		if _, ok := holders[instance.InstanceID]; !ok && loaded.Status == domain.InstanceOnLoan {
+			r.addViolation(instancesFile, record, instance.InstanceID, "instance is on loan but no reader holds it")
+		}
+
+		// the dumps keep no holds, so a copy on the hold shelf is kept for
+		// nobody and would never be freed
+		if _, ok := r.readyHoldByInstance(instance.InstanceID); !ok && loaded.Status == domain.InstanceOnHoldShelf {
+			r.addViolation(instancesFile, record, instance.InstanceID, "instance is on the hold shelf but no hold is ready for it, the instance is loaded as %s", domain.InstanceAvailable)
+			loaded.Status = domain.InstanceAvailable
+			r.instance[instance.InstanceID] = loaded
+		}
+	}
+}
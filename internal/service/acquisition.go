@@ -0,0 +1,72 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetFunds() []domain.FundMapField {
+	return s.r.GetFunds()
+}
+
+func (s *Service) CreateOrder(actor domain.Actor, productionID int, fundName string) (*domain.OrderMapField, error) {
+	order, err := s.r.CreateOrder(actor, productionID, fundName)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) AddOrderLine(actor domain.Actor, orderID, bookID, quantity, unitPrice int) (*domain.OrderMapField, error) {
+	order, err := s.r.AddOrderLine(actor, orderID, bookID, quantity, unitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) SendOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error) {
+	order, err := s.r.SendOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) ReceiveOrder(actor domain.Actor, orderID, bookID, quantity int) (*domain.OrderMapField, error) {
+	order, err := s.r.ReceiveOrder(actor, orderID, bookID, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) CancelOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error) {
+	order, err := s.r.CancelOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) GetOrder(orderID int) (*domain.OrderMapField, error) {
+	order, err := s.r.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) GetProductionOrders(productionID int) ([]domain.OrderMapField, error) {
+	orders, err := s.r.GetProductionOrders(productionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,15 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
+)
+
+func (s *Service) GetAuditLog(principal domain.Principal, filter audit.Filter) ([]audit.Entry, error) {
+	entries, err := s.r.GetAuditLog(principal, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
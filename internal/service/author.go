@@ -0,0 +1,41 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetAuthor(authorID int) (*domain.AuthorEntry, error) {
+	author, err := s.r.GetAuthor(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (s *Service) CreateAuthor(actor domain.Actor, author domain.AuthorMapField) (*domain.AuthorEntry, error) {
+	created, err := s.r.CreateAuthor(actor, author)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) UpdateAuthor(actor domain.Actor, authorID int, author domain.AuthorMapField) (*domain.AuthorEntry, error) {
+	updated, err := s.r.UpdateAuthor(actor, authorID, author)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteAuthor(actor domain.Actor, authorID int) (*domain.DeleteResult, error) {
+	result, err := s.r.DeleteAuthor(actor, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
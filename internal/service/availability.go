@@ -0,0 +1,51 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"strings"
+)
+
+func (s *Service) CheckBookAvailability(bookID, branchID int) (*domain.BookAvailability, error) {
+	availability, err := s.r.CheckBookAvailability(bookID, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return availability, nil
+}
+
+func (s *Service) GetBooksAvailability(branchID int) ([]domain.BookAvailability, error) {
+	availability, err := s.r.GetBooksAvailability(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return availability, nil
+}
+
+// TakeBookCopy lends the reader any available copy of the book. Every copy
+// of a book has the same item category, so the policy is evaluated on the
+// copy the reader would get right now.
+func (s *Service) TakeBookCopy(readerID, bookID int) (*domain.BookCheckout, error) {
+	instanceID, err := s.r.FindAvailableInstance(readerID, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.ExplainCheckout(readerID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !decision.Allowed {
+		return nil, fmt.Errorf("checkout denied: %s", strings.Join(decision.Reasons, "; "))
+	}
+
+	checkout, err := s.r.TakeAnyInstance(readerID, bookID, *decision.Terms)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
@@ -0,0 +1,33 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"time"
+)
+
+func (s *Service) BanUser(actor domain.Actor, userID int, reason string, duration time.Duration) (*domain.BanMapField, error) {
+	ban, err := s.r.BanUser(actor, userID, reason, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (s *Service) UnbanUser(actor domain.Actor, userID int, reason string) (*domain.BanMapField, error) {
+	ban, err := s.r.UnbanUser(actor, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (s *Service) GetUserBans(userID int) ([]domain.BanMapField, error) {
+	bans, err := s.r.GetUserBans(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return bans, nil
+}
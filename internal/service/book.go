@@ -0,0 +1,68 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetBookByISBN(isbn string) (*domain.BookEntry, error) {
+	book, err := s.r.GetBookByISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (s *Service) GetBook(bookID int) (*domain.BookEntry, error) {
+	book, err := s.r.GetBook(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (s *Service) CreateBook(actor domain.Actor, book domain.BookMapField) (*domain.BookEntry, error) {
+	created, err := s.r.CreateBook(actor, book)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) UpdateBook(actor domain.Actor, bookID int, book domain.BookMapField) (*domain.BookEntry, error) {
+	updated, err := s.r.UpdateBook(actor, bookID, book)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteBook(actor domain.Actor, bookID int) (*domain.DeleteResult, error) {
+	result, err := s.r.DeleteBook(actor, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) Search(query string, limit int) ([]domain.SearchResult, error) {
+	results, err := s.r.Search(query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *Service) GetCatalog(filter domain.CatalogFilter) (*domain.CatalogListing, error) {
+	listing, err := s.r.GetCatalog(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
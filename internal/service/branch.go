@@ -0,0 +1,63 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetBranches() map[int]domain.BranchMapField {
+	return s.r.GetBranches()
+}
+
+func (s *Service) RequestTransfer(readerID, bookID, branchID int) (*domain.TransferMapField, error) {
+	transfer, err := s.r.RequestTransfer(readerID, bookID, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *Service) DispatchTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error) {
+	transfer, err := s.r.DispatchTransfer(actor, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *Service) ReceiveTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error) {
+	transfer, err := s.r.ReceiveTransfer(actor, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *Service) CancelTransfer(transferID, readerID int) error {
+	err := s.r.CancelTransfer(transferID, readerID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetTransfer(transferID int) (*domain.TransferMapField, error) {
+	transfer, err := s.r.GetTransfer(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *Service) GetBranchTransfers(branchID int) ([]domain.TransferMapField, error) {
+	transfers, err := s.r.GetBranchTransfers(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
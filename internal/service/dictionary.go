@@ -0,0 +1,113 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetGenre(genreID int) (*domain.GenreEntry, error) {
+	genre, err := s.r.GetGenre(genreID)
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+func (s *Service) CreateGenre(actor domain.Actor, genre domain.GenreMapField) (*domain.GenreEntry, error) {
+	created, err := s.r.CreateGenre(actor, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) UpdateGenre(actor domain.Actor, genreID int, genre domain.GenreMapField) (*domain.GenreEntry, error) {
+	updated, err := s.r.UpdateGenre(actor, genreID, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteGenre(actor domain.Actor, genreID int) (*domain.DeleteResult, error) {
+	result, err := s.r.DeleteGenre(actor, genreID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetLanguage(languageID int) (*domain.LanguageEntry, error) {
+	language, err := s.r.GetLanguage(languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return language, nil
+}
+
+func (s *Service) CreateLanguage(actor domain.Actor, language domain.LanguageMapField) (*domain.LanguageEntry, error) {
+	created, err := s.r.CreateLanguage(actor, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) UpdateLanguage(actor domain.Actor, languageID int, language domain.LanguageMapField) (*domain.LanguageEntry, error) {
+	updated, err := s.r.UpdateLanguage(actor, languageID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteLanguage(actor domain.Actor, languageID int) (*domain.DeleteResult, error) {
+	result, err := s.r.DeleteLanguage(actor, languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetProduction(productionID int) (*domain.ProductionEntry, error) {
+	production, err := s.r.GetProduction(productionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return production, nil
+}
+
+func (s *Service) CreateProduction(actor domain.Actor, production domain.ProductionMapField) (*domain.ProductionEntry, error) {
+	created, err := s.r.CreateProduction(actor, production)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) UpdateProduction(actor domain.Actor, productionID int, production domain.ProductionMapField) (*domain.ProductionEntry, error) {
+	updated, err := s.r.UpdateProduction(actor, productionID, production)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteProduction(actor domain.Actor, productionID int) (*domain.DeleteResult, error) {
+	result, err := s.r.DeleteProduction(actor, productionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetFines(readerID int) (*domain.FineAccount, error) {
+	account, err := s.r.GetFines(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *Service) PayFine(actor domain.Actor, readerID, amount int) (*domain.FineAccount, error) {
+	account, err := s.r.PayFine(actor, readerID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *Service) WaiveFine(actor domain.Actor, readerID, amount int, reason string) (*domain.FineAccount, error) {
+	account, err := s.r.WaiveFine(actor, readerID, amount, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
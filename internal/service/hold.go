@@ -0,0 +1,41 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) PlaceHold(readerID, bookID int) (*domain.HoldMapField, error) {
+	hold, err := s.r.PlaceHold(readerID, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (s *Service) CancelHold(readerID, holdID int) error {
+	err := s.r.CancelHold(readerID, holdID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetReaderHolds(readerID int) ([]domain.HoldMapField, error) {
+	holds, err := s.r.GetReaderHolds(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+func (s *Service) GetBookHolds(bookID int) ([]domain.HoldMapField, error) {
+	holds, err := s.r.GetBookHolds(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
@@ -0,0 +1,77 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) ListBooks(req domain.PageRequest) (*domain.Page[domain.BookEntry], error) {
+	page, err := s.r.ListBooks(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListAuthors(req domain.PageRequest) (*domain.Page[domain.AuthorEntry], error) {
+	page, err := s.r.ListAuthors(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListGenres(req domain.PageRequest) (*domain.Page[domain.GenreEntry], error) {
+	page, err := s.r.ListGenres(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListLanguages(req domain.PageRequest) (*domain.Page[domain.LanguageEntry], error) {
+	page, err := s.r.ListLanguages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListProductions(req domain.PageRequest) (*domain.Page[domain.ProductionEntry], error) {
+	page, err := s.r.ListProductions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListInstances(req domain.PageRequest) (*domain.Page[domain.InstanceEntry], error) {
+	page, err := s.r.ListInstances(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListUsers(req domain.PageRequest) (*domain.Page[domain.UserEntry], error) {
+	page, err := s.r.ListUsers(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *Service) ListReaders(req domain.PageRequest) (*domain.Page[domain.ReaderEntry], error) {
+	page, err := s.r.ListReaders(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,10 @@
+package book_inventory_system_service
+
+func (s *Service) VerifyPassword(userID int, password string) (bool, error) {
+	ok, err := s.r.VerifyPassword(userID, password)
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
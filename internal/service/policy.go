@@ -0,0 +1,111 @@
+package book_inventory_system_service
+
+import (
+	config "book-inventory-system/internal/config"
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+)
+
+const anyCategory = "*"
+
+// ExplainCheckout evaluates the lending policy for the reader taking the
+// instance and lists every reason the checkout would be denied.
+func (s *Service) ExplainCheckout(readerID, instanceID int) (*domain.CheckoutDecision, error) {
+	checkout, err := s.r.GetCheckoutContext(readerID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &domain.CheckoutDecision{
+		ReaderID:       readerID,
+		InstanceID:     instanceID,
+		ReaderCategory: checkout.ReaderCategory,
+		ItemCategory:   checkout.ItemCategory,
+		OpenLoans:      checkout.OpenLoans,
+		Reasons:        checkout.Problems,
+	}
+
+	rule, ok := s.lendingRule(checkout.ReaderCategory, checkout.ItemCategory)
+	if !ok {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf(
+			"no lending rule for reader category %q and item category %q",
+			checkout.ReaderCategory,
+			checkout.ItemCategory,
+		))
+	} else {
+		terms := s.loanTerms(checkout.ReaderCategory, checkout.ItemCategory, rule)
+		decision.Terms = &terms
+
+		switch {
+		case terms.MaxLoans == 0:
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf(
+				"items of category %q are not loanable to readers of category %q",
+				checkout.ItemCategory,
+				checkout.ReaderCategory,
+			))
+		case checkout.OpenLoans >= terms.MaxLoans:
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf(
+				"loan limit %d for items of category %q reached",
+				terms.MaxLoans,
+				checkout.ItemCategory,
+			))
+		}
+	}
+
+	decision.Allowed = len(decision.Reasons) == 0
+
+	return decision, nil
+}
+
+// lendingRule finds the rule for the combination of categories, preferring
+// exact matches over wildcards and the reader category over the item one.
+func (s *Service) lendingRule(readerCategory, itemCategory string) (config.LendingRule, bool) {
+	candidates := [][2]string{
+		{readerCategory, itemCategory},
+		{readerCategory, anyCategory},
+		{anyCategory, itemCategory},
+		{anyCategory, anyCategory},
+	}
+
+	for _, candidate := range candidates {
+		for _, rule := range s.cfg.LendingPolicy.Rules {
+			if rule.ReaderCategory == candidate[0] && rule.ItemCategory == candidate[1] {
+				return rule, true
+			}
+		}
+	}
+
+	return config.LendingRule{}, false
+}
+
+// loanTerms are the terms of the rule, with the global settings for what the
+// rule leaves out.
+func (s *Service) loanTerms(readerCategory, itemCategory string, rule config.LendingRule) domain.LoanTerms {
+	terms := domain.LoanTerms{
+		ReaderCategory: readerCategory,
+		ItemCategory:   itemCategory,
+		MaxLoans:       rule.MaxLoans,
+		LoanPeriod:     rule.LoanPeriod,
+		MaxRenewals:    s.cfg.MaxRenewals,
+		FineRate:       s.cfg.FineDailyRate,
+		FineCap:        s.cfg.FineCap,
+	}
+
+	if terms.LoanPeriod == 0 {
+		terms.LoanPeriod = s.cfg.LoanPeriod
+	}
+
+	if rule.MaxRenewals != nil {
+		terms.MaxRenewals = *rule.MaxRenewals
+	}
+
+	if rule.FineDailyRate != nil {
+		terms.FineRate = *rule.FineDailyRate
+	}
+
+	if rule.FineCap != nil {
+		terms.FineCap = *rule.FineCap
+	}
+
+	return terms
+}
@@ -0,0 +1,53 @@
+package book_inventory_system_service
+
+import (
+	config "book-inventory-system/internal/config"
+	domain "book-inventory-system/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestLoanTerms(t *testing.T) {
+	s := &Service{cfg: &config.Config{
+		LoanPeriod:    14 * 24 * time.Hour,
+		MaxRenewals:   2,
+		FineDailyRate: 1000,
+		FineCap:       50000,
+	}}
+
+	zero := 0
+	four := 4
+
+	tests := []struct {
+		name string
+		rule config.LendingRule
+		want domain.LoanTerms
+	}{
+		{
+			name: "everything omitted",
+			rule: config.LendingRule{MaxLoans: 5},
+			want: domain.LoanTerms{MaxLoans: 5, LoanPeriod: 14 * 24 * time.Hour, MaxRenewals: 2, FineRate: 1000, FineCap: 50000},
+		},
+		{
+			name: "explicit zeros",
+			rule: config.LendingRule{MaxLoans: 5, MaxRenewals: &zero, FineDailyRate: &zero, FineCap: &zero},
+			want: domain.LoanTerms{MaxLoans: 5, LoanPeriod: 14 * 24 * time.Hour},
+		},
+		{
+			name: "everything set",
+			rule: config.LendingRule{MaxLoans: 10, LoanPeriod: 28 * 24 * time.Hour, MaxRenewals: &four, FineDailyRate: &four, FineCap: &four},
+			want: domain.LoanTerms{MaxLoans: 10, LoanPeriod: 28 * 24 * time.Hour, MaxRenewals: 4, FineRate: 4, FineCap: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.ReaderCategory = "staff"
+			tt.want.ItemCategory = "standard"
+
+			if got := s.loanTerms("staff", "standard", tt.rule); got != tt.want {
+				t.Errorf("loanTerms = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+	"fmt"
+	"time"
+)
+
+// AuthenticateSession returns the principal of an active session.
+func (s *Service) AuthenticateSession(sessionToken string) (*domain.Principal, error) {
+	session, err := s.r.Authenticate(sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := s.r.Principal(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	principal.Method = domain.AuthSession
+
+	return principal, nil
+}
+
+// AuthenticateToken returns the principal of a signed bearer token.
+func (s *Service) AuthenticateToken(bearerToken string) (*domain.Principal, error) {
+	if s.cfg.TokenSecret == "" {
+		return nil, fmt.Errorf("bearer tokens are not configured")
+	}
+
+	claims, err := s.signer.Verify(bearerToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := s.r.Principal(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	principal.Method = domain.AuthBearer
+
+	return principal, nil
+}
+
+// IssueToken signs a bearer token for the principal, for clients that
+// cannot keep a session cookie. Only a session login earns a token: a token
+// issued for a token would renew itself forever and outlive a logout.
+func (s *Service) IssueToken(principal domain.Principal) (*domain.BearerToken, error) {
+	if s.cfg.TokenSecret == "" {
+		return nil, fmt.Errorf("bearer tokens are not configured")
+	}
+
+	if principal.Method != domain.AuthSession {
+		return nil, fmt.Errorf("tokens are issued to session logins only")
+	}
+
+	bearerToken, claims, err := s.signer.Sign(principal.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BearerToken{
+		Token:      bearerToken,
+		UserID:     claims.UserID,
+		ExpireDate: time.Unix(claims.ExpireDate, 0).UTC(),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package book_inventory_system_service
+
+import (
+	config "book-inventory-system/internal/config"
+	domain "book-inventory-system/internal/domain"
+	token "book-inventory-system/pkg/token"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueToken(t *testing.T) {
+	s := &Service{
+		cfg:    &config.Config{TokenSecret: "secret"},
+		signer: token.NewSigner([]byte("secret"), time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		wantErr string
+	}{
+		{name: "session login", method: domain.AuthSession},
+		{name: "bearer token", method: domain.AuthBearer, wantErr: "session logins only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := domain.Principal{UserID: 7, Roles: []string{domain.RoleReader}, Method: tt.method}
+
+			issued, err := s.IssueToken(principal)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("IssueToken = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("IssueToken: %v", err)
+			}
+
+			if issued.UserID != principal.UserID {
+				t.Errorf("token user = %d, want %d", issued.UserID, principal.UserID)
+			}
+		})
+	}
+}
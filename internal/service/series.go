@@ -0,0 +1,23 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) GetSeries(seriesID, branchID int) (*domain.SeriesListing, error) {
+	series, err := s.r.GetSeries(seriesID, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *Service) NextInSeries(readerID int) ([]domain.SeriesSuggestion, error) {
+	suggestions, err := s.r.NextInSeries(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
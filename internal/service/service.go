@@ -3,24 +3,106 @@ package book_inventory_system_service
 import (
 	config "book-inventory-system/internal/config"
 	domain "book-inventory-system/internal/domain"
+	audit "book-inventory-system/pkg/audit"
 	logger "book-inventory-system/pkg/logger"
+	token "book-inventory-system/pkg/token"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type repository interface {
-	ReturnBook(id int) error
-	TakeBook(id int) (*domain.BookMapField, error)
-	UpdateLoginStatus(id int, status string) error
-	BanUser(userID, adminID int) error
-	UpdateInstanceStatus(instanceID, status int) error
+	ReturnBook(readerID, instanceID int) error
+	TakeBook(readerID, instanceID int, terms domain.LoanTerms) (*domain.BookMapField, error)
+	Login(name, password string) (*domain.Login, error)
+	Logout(token string) error
+	Authenticate(token string) (*domain.SessionMapField, error)
+	GetUserSessions(principal domain.Principal, userID int) ([]domain.SessionMapField, error)
+	RevokeSession(actor domain.Actor, sessionID int) (*domain.SessionMapField, error)
+	Principal(userID int) (*domain.Principal, error)
+	VerifyPassword(userID int, password string) (bool, error)
+	BanUser(actor domain.Actor, userID int, reason string, duration time.Duration) (*domain.BanMapField, error)
+	UnbanUser(actor domain.Actor, userID int, reason string) (*domain.BanMapField, error)
+	GetUserBans(userID int) ([]domain.BanMapField, error)
+	UpdateInstanceStatus(actor domain.Actor, instanceID int, status domain.InstanceStatus) error
+	GetAuditLog(principal domain.Principal, filter audit.Filter) ([]audit.Entry, error)
 	CheckAvailability(instanceID int) (bool, error)
 	CountPublishedBooks(authorID int) (int, error)
 	CheckBorrowBooks(readerID int) ([]domain.BookMapField, error)
+	GetBookByISBN(isbn string) (*domain.BookEntry, error)
+	GetBook(bookID int) (*domain.BookEntry, error)
+	CreateBook(actor domain.Actor, book domain.BookMapField) (*domain.BookEntry, error)
+	UpdateBook(actor domain.Actor, bookID int, book domain.BookMapField) (*domain.BookEntry, error)
+	DeleteBook(actor domain.Actor, bookID int) (*domain.DeleteResult, error)
+	GetAuthor(authorID int) (*domain.AuthorEntry, error)
+	CreateAuthor(actor domain.Actor, author domain.AuthorMapField) (*domain.AuthorEntry, error)
+	UpdateAuthor(actor domain.Actor, authorID int, author domain.AuthorMapField) (*domain.AuthorEntry, error)
+	DeleteAuthor(actor domain.Actor, authorID int) (*domain.DeleteResult, error)
+	GetGenre(genreID int) (*domain.GenreEntry, error)
+	CreateGenre(actor domain.Actor, genre domain.GenreMapField) (*domain.GenreEntry, error)
+	UpdateGenre(actor domain.Actor, genreID int, genre domain.GenreMapField) (*domain.GenreEntry, error)
+	DeleteGenre(actor domain.Actor, genreID int) (*domain.DeleteResult, error)
+	GetLanguage(languageID int) (*domain.LanguageEntry, error)
+	CreateLanguage(actor domain.Actor, language domain.LanguageMapField) (*domain.LanguageEntry, error)
+	UpdateLanguage(actor domain.Actor, languageID int, language domain.LanguageMapField) (*domain.LanguageEntry, error)
+	DeleteLanguage(actor domain.Actor, languageID int) (*domain.DeleteResult, error)
+	GetProduction(productionID int) (*domain.ProductionEntry, error)
+	CreateProduction(actor domain.Actor, production domain.ProductionMapField) (*domain.ProductionEntry, error)
+	UpdateProduction(actor domain.Actor, productionID int, production domain.ProductionMapField) (*domain.ProductionEntry, error)
+	DeleteProduction(actor domain.Actor, productionID int) (*domain.DeleteResult, error)
+	GetSeries(seriesID, branchID int) (*domain.SeriesListing, error)
+	NextInSeries(readerID int) ([]domain.SeriesSuggestion, error)
+	Search(query string, limit int) ([]domain.SearchResult, error)
+	GetCatalog(filter domain.CatalogFilter) (*domain.CatalogListing, error)
+	ListBooks(req domain.PageRequest) (*domain.Page[domain.BookEntry], error)
+	ListAuthors(req domain.PageRequest) (*domain.Page[domain.AuthorEntry], error)
+	ListGenres(req domain.PageRequest) (*domain.Page[domain.GenreEntry], error)
+	ListLanguages(req domain.PageRequest) (*domain.Page[domain.LanguageEntry], error)
+	ListProductions(req domain.PageRequest) (*domain.Page[domain.ProductionEntry], error)
+	ListInstances(req domain.PageRequest) (*domain.Page[domain.InstanceEntry], error)
+	ListUsers(req domain.PageRequest) (*domain.Page[domain.UserEntry], error)
+	ListReaders(req domain.PageRequest) (*domain.Page[domain.ReaderEntry], error)
+	GetInstanceLoan(instanceID int) (*domain.LoanMapField, error)
+	GetReaderLoans(readerID int) ([]domain.LoanMapField, error)
+	RenewLoan(readerID, loanID int) (*domain.LoanMapField, error)
+	GetCheckoutContext(readerID, instanceID int) (*domain.CheckoutContext, error)
+	CheckBookAvailability(bookID, branchID int) (*domain.BookAvailability, error)
+	GetBooksAvailability(branchID int) ([]domain.BookAvailability, error)
+	FindAvailableInstance(readerID, bookID int) (int, error)
+	TakeAnyInstance(readerID, bookID int, terms domain.LoanTerms) (*domain.BookCheckout, error)
+	OpenStocktake(actor domain.Actor, branchID int) (*domain.StocktakeMapField, error)
+	ScanStocktake(stocktakeID int, instanceIDs []int) (*domain.StocktakeMapField, error)
+	CloseStocktake(actor domain.Actor, stocktakeID int, markLost bool) (*domain.StocktakeMapField, error)
+	GetStocktake(stocktakeID int) (*domain.StocktakeMapField, error)
+	GetBranches() map[int]domain.BranchMapField
+	RequestTransfer(readerID, bookID, branchID int) (*domain.TransferMapField, error)
+	DispatchTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error)
+	ReceiveTransfer(actor domain.Actor, transferID int) (*domain.TransferMapField, error)
+	CancelTransfer(transferID, readerID int) error
+	GetTransfer(transferID int) (*domain.TransferMapField, error)
+	GetBranchTransfers(branchID int) ([]domain.TransferMapField, error)
+	GetFunds() []domain.FundMapField
+	CreateOrder(actor domain.Actor, productionID int, fundName string) (*domain.OrderMapField, error)
+	AddOrderLine(actor domain.Actor, orderID, bookID, quantity, unitPrice int) (*domain.OrderMapField, error)
+	SendOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error)
+	ReceiveOrder(actor domain.Actor, orderID, bookID, quantity int) (*domain.OrderMapField, error)
+	CancelOrder(actor domain.Actor, orderID int) (*domain.OrderMapField, error)
+	GetOrder(orderID int) (*domain.OrderMapField, error)
+	GetProductionOrders(productionID int) ([]domain.OrderMapField, error)
+	PlaceHold(readerID, bookID int) (*domain.HoldMapField, error)
+	CancelHold(readerID, holdID int) error
+	GetReaderHolds(readerID int) ([]domain.HoldMapField, error)
+	GetBookHolds(bookID int) ([]domain.HoldMapField, error)
+	GetFines(readerID int) (*domain.FineAccount, error)
+	PayFine(actor domain.Actor, readerID, amount int) (*domain.FineAccount, error)
+	WaiveFine(actor domain.Actor, readerID, amount int, reason string) (*domain.FineAccount, error)
 }
 
 type Service struct {
-	r   repository
-	l   logger.Logger
-	cfg *config.Config
+	r      repository
+	l      logger.Logger
+	cfg    *config.Config
+	signer *token.Signer
 }
 
 func New(
@@ -29,14 +111,15 @@ func New(
 	cfg *config.Config,
 ) *Service {
 	return &Service{
-		r:   r,
-		l:   l,
-		cfg: cfg,
+		r:      r,
+		l:      l,
+		cfg:    cfg,
+		signer: token.NewSigner([]byte(cfg.TokenSecret), cfg.TokenTTL),
 	}
 }
 
-func (s *Service) ReturnBook(id int) error {
-	err := s.r.ReturnBook(id)
+func (s *Service) ReturnBook(readerID, instanceID int) error {
+	err := s.r.ReturnBook(readerID, instanceID)
 	if err != nil {
 		return err
 	}
@@ -44,35 +127,26 @@ func (s *Service) ReturnBook(id int) error {
 	return nil
 }
 
-func (s *Service) TakeBook(id int) (*domain.BookMapField, error) {
-	book, err := s.r.TakeBook(id)
+func (s *Service) TakeBook(readerID, instanceID int) (*domain.BookMapField, error) {
+	decision, err := s.ExplainCheckout(readerID, instanceID)
 	if err != nil {
 		return nil, err
 	}
 
-	return book, nil
-}
-
-func (s *Service) UpdateLoginStatus(id int, status string) error {
-	err := s.r.UpdateLoginStatus(id, status)
-	if err != nil {
-		return err
+	if !decision.Allowed {
+		return nil, fmt.Errorf("checkout denied: %s", strings.Join(decision.Reasons, "; "))
 	}
 
-	return nil
-}
-
-func (s *Service) BanUser(userID, adminID int) error {
-	err := s.r.BanUser(userID, adminID)
+	book, err := s.r.TakeBook(readerID, instanceID, *decision.Terms)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return book, nil
 }
 
-func (s *Service) UpdateInstanceStatus(instanceID, status int) error {
-	err := s.r.UpdateInstanceStatus(instanceID, status)
+func (s *Service) UpdateInstanceStatus(actor domain.Actor, instanceID int, status domain.InstanceStatus) error {
+	err := s.r.UpdateInstanceStatus(actor, instanceID, status)
 	if err != nil {
 		return err
 	}
@@ -106,3 +180,30 @@ func (s *Service) CheckBorrowBooks(readerID int) ([]domain.BookMapField, error)
 
 	return books, nil
 }
+
+func (s *Service) GetInstanceLoan(instanceID int) (*domain.LoanMapField, error) {
+	loan, err := s.r.GetInstanceLoan(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+func (s *Service) GetReaderLoans(readerID int) ([]domain.LoanMapField, error) {
+	loans, err := s.r.GetReaderLoans(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loans, nil
+}
+
+func (s *Service) RenewLoan(readerID, loanID int) (*domain.LoanMapField, error) {
+	loan, err := s.r.RenewLoan(readerID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
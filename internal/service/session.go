@@ -0,0 +1,50 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) Login(name, password string) (*domain.Login, error) {
+	login, err := s.r.Login(name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+func (s *Service) Logout(token string) error {
+	err := s.r.Logout(token)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) Authenticate(token string) (*domain.SessionMapField, error) {
+	session, err := s.r.Authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *Service) GetUserSessions(principal domain.Principal, userID int) ([]domain.SessionMapField, error) {
+	sessions, err := s.r.GetUserSessions(principal, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *Service) RevokeSession(actor domain.Actor, sessionID int) (*domain.SessionMapField, error) {
+	session, err := s.r.RevokeSession(actor, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
@@ -0,0 +1,41 @@
+package book_inventory_system_service
+
+import (
+	domain "book-inventory-system/internal/domain"
+)
+
+func (s *Service) OpenStocktake(actor domain.Actor, branchID int) (*domain.StocktakeMapField, error) {
+	stocktake, err := s.r.OpenStocktake(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+func (s *Service) ScanStocktake(stocktakeID int, instanceIDs []int) (*domain.StocktakeMapField, error) {
+	stocktake, err := s.r.ScanStocktake(stocktakeID, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+func (s *Service) CloseStocktake(actor domain.Actor, stocktakeID int, markLost bool) (*domain.StocktakeMapField, error) {
+	stocktake, err := s.r.CloseStocktake(actor, stocktakeID, markLost)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+func (s *Service) GetStocktake(stocktakeID int) (*domain.StocktakeMapField, error) {
+	stocktake, err := s.r.GetStocktake(stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
@@ -0,0 +1,230 @@
+package book_inventory_system_audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/goccy/go-json"
+	"os"
+	"time"
+)
+
+var ErrBrokenChain = errors.New("audit log hash chain is broken")
+
+// Entry is one recorded action. Hash is the SHA-256 of PrevHash followed by
+// the JSON encoding of the entry without its hash, so changing, removing or
+// reordering entries breaks the chain of every later one.
+type Entry struct {
+	Seq       int             `json:"seq"`
+	Time      time.Time       `json:"time"`
+	RequestID string          `json:"request_id,omitempty"`
+	Actor     int             `json:"actor"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  int             `json:"entity_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+// Filter selects entries. Zero fields match any entry, From is inclusive and
+// To exclusive.
+type Filter struct {
+	Actor    int
+	Entity   string
+	EntityID int
+	From     time.Time
+	To       time.Time
+}
+
+func (f Filter) matches(entry Entry) bool {
+	switch {
+	case f.Actor != 0 && entry.Actor != f.Actor:
+		return false
+	case f.Entity != "" && entry.Entity != f.Entity:
+		return false
+	case f.EntityID != 0 && entry.EntityID != f.EntityID:
+		return false
+	case !f.From.IsZero() && entry.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !entry.Time.Before(f.To):
+		return false
+	}
+
+	return true
+}
+
+// Log is an append-only JSON lines file of hash-chained entries. It is not
+// safe for concurrent use.
+type Log struct {
+	file    *os.File
+	entries []Entry
+}
+
+// Open opens the log at path, creating it when it does not exist, and
+// verifies the chain of the entries already in it.
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Log{
+		file:    file,
+		entries: make([]Entry, 0),
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry Entry
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("audit log entry %d: %w", len(l.entries)+1, err)
+		}
+
+		l.entries = append(l.entries, entry)
+	}
+
+	if err = scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if err = l.Verify(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Append records an action. before and after are encoded as JSON, nil leaves
+// them out.
+func (l *Log) Append(requestID string, actor int, action, entity string, entityID int, before, after any) (Entry, error) {
+	entry := Entry{
+		Seq:       len(l.entries) + 1,
+		Time:      time.Now().UTC(),
+		RequestID: requestID,
+		Actor:     actor,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+	}
+
+	if len(l.entries) > 0 {
+		entry.PrevHash = l.entries[len(l.entries)-1].Hash
+	}
+
+	var err error
+	if before != nil {
+		entry.Before, err = json.Marshal(before)
+		if err != nil {
+			return entry, err
+		}
+	}
+
+	if after != nil {
+		entry.After, err = json.Marshal(after)
+		if err != nil {
+			return entry, err
+		}
+	}
+
+	entry.Hash, err = hash(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	info, err := l.file.Stat()
+	if err != nil {
+		return entry, err
+	}
+
+	// a failed write may leave part of the line behind, which would break
+	// the chain for good, so the file is cut back to the last whole entry
+	_, err = l.file.Write(append(line, '\n'))
+	if err == nil {
+		err = l.file.Sync()
+	}
+
+	if err != nil {
+		_ = l.file.Truncate(info.Size())
+		return entry, err
+	}
+
+	l.entries = append(l.entries, entry)
+
+	return entry, nil
+}
+
+// Query returns the entries matching the filter in the order they were
+// recorded.
+func (l *Log) Query(filter Filter) []Entry {
+	entries := make([]Entry, 0)
+	for _, entry := range l.entries {
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Verify checks the sequence numbers and the hash chain of every entry.
+func (l *Log) Verify() error {
+	prevHash := ""
+	for i, entry := range l.entries {
+		if entry.Seq != i+1 {
+			return fmt.Errorf("%w: entry %d has sequence number %d", ErrBrokenChain, i+1, entry.Seq)
+		}
+
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("%w: entry %d does not follow entry %d", ErrBrokenChain, entry.Seq, entry.Seq-1)
+		}
+
+		sum, err := hash(entry)
+		if err != nil {
+			return err
+		}
+
+		if entry.Hash != sum {
+			return fmt.Errorf("%w: entry %d has been modified", ErrBrokenChain, entry.Seq)
+		}
+
+		prevHash = entry.Hash
+	}
+
+	return nil
+}
+
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+func hash(entry Entry) (string, error) {
+	entry.Hash = ""
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(entry.PrevHash), payload...))
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,179 @@
+package book_inventory_system_audit
+
+import (
+	"bytes"
+	"errors"
+	"github.com/goccy/go-json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLog appends three entries to a new log and returns its path and
+// lines.
+func writeLog(t *testing.T) (string, [][]byte) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	changes := []struct {
+		action string
+		before any
+		after  any
+	}{
+		{"create", nil, map[string]int{"quantity": 1}},
+		{"update", map[string]int{"quantity": 1}, map[string]int{"quantity": 2}},
+		{"delete", map[string]int{"quantity": 2}, nil},
+	}
+
+	for _, change := range changes {
+		_, err = l.Append("request", 123, change.action, "book", 1, change.before, change.after)
+		if err != nil {
+			t.Fatalf("Append(%q): %v", change.action, err)
+		}
+	}
+
+	if err = l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	return path, bytes.SplitAfter(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+func rewriteLog(t *testing.T, path string, lines ...[]byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, bytes.Join(lines, nil), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	path, _ := writeLog(t)
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+
+	entries := l.Query(Filter{})
+	if len(entries) != 3 {
+		t.Fatalf("Query returned %d entries, want 3", len(entries))
+	}
+
+	entry, err := l.Append("request", 123, "create", "book", 2, nil, nil)
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	if entry.Seq != 4 || entry.PrevHash != entries[2].Hash {
+		t.Errorf("Append after reopening = seq %d prev %q, want seq 4 prev %q", entry.Seq, entry.PrevHash, entries[2].Hash)
+	}
+
+	if err = l.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	path, lines := writeLog(t)
+
+	var entry Entry
+	if err := json.Unmarshal(lines[1], &entry); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	// the hashes are kept, so only recomputing them can tell
+	entry.After = json.RawMessage(`{"quantity":20}`)
+	tampered, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		lines [][]byte
+	}{
+		{"modified entry", [][]byte{lines[0], append(tampered, '\n'), lines[2]}},
+		{"swapped entries", [][]byte{lines[1], lines[0], lines[2]}},
+		{"first entry removed", [][]byte{lines[1], lines[2]}},
+		{"middle entry removed", [][]byte{lines[0], lines[2]}},
+		{"entry repeated", [][]byte{lines[0], lines[1], lines[1], lines[2]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewriteLog(t, path, tt.lines...)
+
+			l, err := Open(path)
+			if err == nil {
+				l.Close()
+			}
+
+			if !errors.Is(err, ErrBrokenChain) {
+				t.Errorf("Open = %v, want %v", err, ErrBrokenChain)
+			}
+		})
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	path, lines := writeLog(t)
+	rewriteLog(t, path, lines[0], lines[1], lines[2][:len(lines[2])/2])
+
+	l, err := Open(path)
+	if err == nil {
+		l.Close()
+		t.Fatal("Open of a log with a partial last entry succeeded")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	path, _ := writeLog(t)
+
+	tests := []struct {
+		name   string
+		tamper func(entries []Entry) []Entry
+	}{
+		{"modified actor", func(entries []Entry) []Entry {
+			entries[0].Actor = 421
+			return entries
+		}},
+		{"modified hash", func(entries []Entry) []Entry {
+			entries[2].Hash = entries[1].Hash
+			return entries
+		}},
+		{"renumbered entry", func(entries []Entry) []Entry {
+			entries[1].Seq = 3
+			return entries
+		}},
+		{"truncated at the start", func(entries []Entry) []Entry {
+			return entries[1:]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer l.Close()
+
+			l.entries = tt.tamper(l.entries)
+			if err = l.Verify(); !errors.Is(err, ErrBrokenChain) {
+				t.Errorf("Verify = %v, want %v", err, ErrBrokenChain)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package book_inventory_system_isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength    = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidCharacter = errors.New("isbn contains an invalid character")
+	ErrInvalidPrefix    = errors.New("isbn-13 must start with 978 or 979")
+	ErrInvalidChecksum  = errors.New("isbn check digit does not match")
+)
+
+// Normalize validates an ISBN-10 or ISBN-13 and returns it as an ISBN-13
+// without separators. Hyphens and spaces are ignored.
+func Normalize(isbn string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(isbn))
+
+	switch len(digits) {
+	case 10:
+		err := validate10(digits)
+		if err != nil {
+			return "", err
+		}
+
+		body := "978" + digits[:9]
+		return body + string(checkDigit13(body)), nil
+
+	case 13:
+		err := validate13(digits)
+		if err != nil {
+			return "", err
+		}
+
+		return digits, nil
+
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// validate10 checks an ISBN-10. Its check digit can be X, which stands for 10.
+func validate10(digits string) error {
+	sum := 0
+	for i, r := range digits {
+		value := int(r - '0')
+		if r == 'X' && i == 9 {
+			value = 10
+		} else if r < '0' || r > '9' {
+			return ErrInvalidCharacter
+		}
+
+		sum += (10 - i) * value
+	}
+
+	if sum%11 != 0 {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+func validate13(digits string) error {
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return ErrInvalidCharacter
+		}
+	}
+
+	if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+		return ErrInvalidPrefix
+	}
+
+	if checkDigit13(digits[:12]) != digits[12] {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// checkDigit13 computes the check digit of the first twelve digits of an
+// ISBN-13, weighted alternately by 1 and 3.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += weight * int(body[i]-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
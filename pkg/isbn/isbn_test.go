@@ -0,0 +1,46 @@
+package book_inventory_system_isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn string
+		want string
+		err  error
+	}{
+		{"isbn-13", "9780306406157", "9780306406157", nil},
+		{"hyphenated isbn-13", "978-0-306-40615-7", "9780306406157", nil},
+		{"spaced isbn-13", "978 0 306 40615 7", "9780306406157", nil},
+		{"isbn-13 with prefix 979", "979-10-90636-07-1", "9791090636071", nil},
+		{"isbn-10", "0306406152", "9780306406157", nil},
+		{"hyphenated isbn-10", "0-306-40615-2", "9780306406157", nil},
+		{"isbn-10 with check digit x", "0-8044-2957-X", "9780804429573", nil},
+		{"isbn-10 with lower case x", "080442957x", "9780804429573", nil},
+		{"isbn-13 with wrong check digit", "978-0-306-40615-8", "", ErrInvalidChecksum},
+		{"isbn-10 with wrong check digit", "0-306-40615-3", "", ErrInvalidChecksum},
+		{"isbn-13 with unknown prefix", "9770306406157", "", ErrInvalidPrefix},
+		{"isbn-13 with a letter", "97803064061A7", "", ErrInvalidCharacter},
+		{"isbn-10 with x before the end", "X306406152", "", ErrInvalidCharacter},
+		{"isbn-10 with a letter", "03064A6152", "", ErrInvalidCharacter},
+		{"too short", "030640615", "", ErrInvalidLength},
+		{"too long", "97803064061570", "", ErrInvalidLength},
+		{"empty", "", "", ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.isbn)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Normalize(%q) error = %v, want %v", tt.isbn, err, tt.err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
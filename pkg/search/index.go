@@ -0,0 +1,104 @@
+package book_inventory_system_search
+
+import (
+	"math"
+	"sort"
+)
+
+// Index is an inverted index of documents made of weighted text fields,
+// ranked with TF-IDF. It is not safe for concurrent use.
+type Index struct {
+	weights  map[string]float64
+	postings map[string]map[int]float64
+	terms    map[int][]string
+}
+
+type Result struct {
+	DocID int
+	Score float64
+}
+
+// New creates an index. weights gives the weight of a term occurrence in each
+// field, fields with no weight count once.
+func New(weights map[string]float64) *Index {
+	return &Index{
+		weights:  weights,
+		postings: make(map[string]map[int]float64),
+		terms:    make(map[int][]string),
+	}
+}
+
+// Add indexes the fields of the document, replacing its previous version.
+func (i *Index) Add(docID int, fields map[string]string) {
+	i.Remove(docID)
+
+	frequencies := make(map[string]float64)
+	for field, text := range fields {
+		weight, ok := i.weights[field]
+		if !ok {
+			weight = 1
+		}
+
+		for _, term := range Tokenize(text) {
+			frequencies[term] += weight
+		}
+	}
+
+	terms := make([]string, 0, len(frequencies))
+	for term, frequency := range frequencies {
+		postings, ok := i.postings[term]
+		if !ok {
+			postings = make(map[int]float64)
+			i.postings[term] = postings
+		}
+
+		postings[docID] = frequency
+		terms = append(terms, term)
+	}
+
+	i.terms[docID] = terms
+}
+
+func (i *Index) Remove(docID int) {
+	for _, term := range i.terms[docID] {
+		delete(i.postings[term], docID)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+
+	delete(i.terms, docID)
+}
+
+// Search returns the documents containing any of the query terms, best match
+// first. A document scores the sum over the query terms of the log-scaled
+// weighted term frequency times the inverse document frequency.
+func (i *Index) Search(query string) []Result {
+	scores := make(map[int]float64)
+	for _, term := range Tokenize(query) {
+		postings := i.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + float64(len(i.terms))/float64(len(postings)))
+		for docID, frequency := range postings {
+			scores[docID] += (1 + math.Log(frequency)) * idf
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for docID, score := range scores {
+		results = append(results, Result{DocID: docID, Score: score})
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+
+		return results[a].DocID < results[b].DocID
+	})
+
+	return results
+}
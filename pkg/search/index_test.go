@@ -0,0 +1,71 @@
+package book_inventory_system_search
+
+import "testing"
+
+func TestIndexSearch(t *testing.T) {
+	index := New(map[string]float64{"name": 3})
+	index.Add(1, map[string]string{"name": "War and Peace", "description": "A novel about Russia"})
+	index.Add(2, map[string]string{"name": "The Art of War"})
+	index.Add(3, map[string]string{"name": "Anna Karenina", "description": "A novel about a war of feelings"})
+	index.Add(4, map[string]string{"name": "Война и мир"})
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"war", []int{1, 2, 3}},
+		{"novels", []int{1, 3}},
+		{"war peace", []int{1, 2, 3}},
+		{"войной", []int{4}},
+		{"the", []int{}},
+		{"dostoevsky", []int{}},
+	}
+
+	for _, tt := range tests {
+		results := index.Search(tt.query)
+		if len(results) != len(tt.want) {
+			t.Errorf("Search(%q) = %v, want documents %v", tt.query, results, tt.want)
+			continue
+		}
+
+		for n, result := range results {
+			if result.DocID != tt.want[n] {
+				t.Errorf("Search(%q) = %v, want documents %v", tt.query, results, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIndexAddReplaces(t *testing.T) {
+	index := New(nil)
+	index.Add(1, map[string]string{"name": "War and Peace"})
+	index.Add(1, map[string]string{"name": "Anna Karenina"})
+
+	if results := index.Search("war"); len(results) != 0 {
+		t.Errorf("Search(%q) = %v, want no documents", "war", results)
+	}
+
+	if results := index.Search("anna"); len(results) != 1 || results[0].DocID != 1 {
+		t.Errorf("Search(%q) = %v, want document 1", "anna", results)
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	index := New(nil)
+	index.Add(1, map[string]string{"name": "War and Peace"})
+	index.Add(2, map[string]string{"name": "The Art of War"})
+	index.Remove(1)
+
+	if results := index.Search("peace"); len(results) != 0 {
+		t.Errorf("Search(%q) = %v, want no documents", "peace", results)
+	}
+
+	if results := index.Search("war"); len(results) != 1 || results[0].DocID != 2 {
+		t.Errorf("Search(%q) = %v, want document 2", "war", results)
+	}
+
+	if len(index.postings["peac"]) != 0 || len(index.terms) != 1 {
+		t.Errorf("Remove(1) left postings %v and terms %v", index.postings, index.terms)
+	}
+}
@@ -0,0 +1,283 @@
+package book_inventory_system_search
+
+import "strings"
+
+// stemEnglish implements the Porter2 (Snowball English) stemmer without its
+// list of irregular exceptions.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	w := []byte(strings.TrimPrefix(word, "'"))
+	for i := range w {
+		if w[i] == 'y' && (i == 0 || isVowelEn(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1, r2 := regionsEn(w)
+
+	w = step0En(w)
+	w = step1aEn(w)
+	w = step1bEn(w, r1)
+	w = step1cEn(w)
+	w = step2En(w, r1)
+	w = step3En(w, r1, r2)
+	w = step4En(w, r2)
+	w = step5En(w, r1, r2)
+
+	return strings.ReplaceAll(string(w), "Y", "y")
+}
+
+func isVowelEn(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	default:
+		return false
+	}
+}
+
+func isDoubleEn(w []byte) bool {
+	if len(w) < 2 || w[len(w)-1] != w[len(w)-2] {
+		return false
+	}
+
+	switch w[len(w)-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	default:
+		return false
+	}
+}
+
+func isLiEndingEn(c byte) bool {
+	return strings.IndexByte("cdeghkmnrt", c) >= 0
+}
+
+// regionsEn returns the starts of the R1 and R2 regions: R1 follows the first
+// non-vowel that follows a vowel, R2 is the same region taken inside R1.
+func regionsEn(w []byte) (int, int) {
+	r1 := len(w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+
+	if r1 == len(w) {
+		r1 = nextRegionEn(w, 0)
+	}
+
+	return r1, nextRegionEn(w, r1)
+}
+
+func nextRegionEn(w []byte, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowelEn(w[i]) && isVowelEn(w[i-1]) {
+			return i + 1
+		}
+	}
+
+	return len(w)
+}
+
+// endsShortSyllableEn reports whether the word ends with a short syllable: a
+// non-vowel, a vowel and a non-vowel other than w, x or Y, or a vowel and a
+// non-vowel at the beginning of the word.
+func endsShortSyllableEn(w []byte) bool {
+	n := len(w)
+	if n == 2 {
+		return isVowelEn(w[0]) && !isVowelEn(w[1])
+	}
+
+	if n < 3 {
+		return false
+	}
+
+	c := w[n-1]
+	return !isVowelEn(w[n-3]) && isVowelEn(w[n-2]) && !isVowelEn(c) && c != 'w' && c != 'x' && c != 'Y'
+}
+
+func hasVowelEn(w []byte) bool {
+	for _, c := range w {
+		if isVowelEn(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// longestSuffix returns the longest of the suffixes the word ends with.
+func longestSuffix(w []byte, suffixes []string) string {
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && strings.HasSuffix(string(w), suffix) {
+			found = suffix
+		}
+	}
+
+	return found
+}
+
+func replaceSuffix(w []byte, suffix, replacement string) []byte {
+	return append(w[:len(w)-len(suffix)], replacement...)
+}
+
+func step0En(w []byte) []byte {
+	suffix := longestSuffix(w, []string{"'", "'s", "'s'"})
+	return w[:len(w)-len(suffix)]
+}
+
+func step1aEn(w []byte) []byte {
+	switch suffix := longestSuffix(w, []string{"sses", "ied", "ies", "us", "ss", "s"}); suffix {
+	case "sses":
+		return replaceSuffix(w, suffix, "ss")
+	case "ied", "ies":
+		if len(w) > 4 {
+			return replaceSuffix(w, suffix, "i")
+		}
+
+		return replaceSuffix(w, suffix, "ie")
+	case "s":
+		if hasVowelEn(w[:len(w)-2]) {
+			return w[:len(w)-1]
+		}
+	}
+
+	return w
+}
+
+func step1bEn(w []byte, r1 int) []byte {
+	switch suffix := longestSuffix(w, []string{"eed", "eedly", "ed", "edly", "ing", "ingly"}); suffix {
+	case "":
+		return w
+	case "eed", "eedly":
+		if len(w)-len(suffix) >= r1 {
+			return replaceSuffix(w, suffix, "ee")
+		}
+
+		return w
+	default:
+		stem := w[:len(w)-len(suffix)]
+		if !hasVowelEn(stem) {
+			return w
+		}
+
+		switch {
+		case strings.HasSuffix(string(stem), "at"), strings.HasSuffix(string(stem), "bl"), strings.HasSuffix(string(stem), "iz"):
+			return append(stem, 'e')
+		case isDoubleEn(stem):
+			return stem[:len(stem)-1]
+		case endsShortSyllableEn(stem) && r1 >= len(stem):
+			return append(stem, 'e')
+		}
+
+		return stem
+	}
+}
+
+func step1cEn(w []byte) []byte {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isVowelEn(w[n-2]) {
+		w[n-1] = 'i'
+	}
+
+	return w
+}
+
+var step2En2 = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able",
+	"entli": "ent", "izer": "ize", "ization": "ize", "ational": "ate",
+	"ation": "ate", "ator": "ate", "alism": "al", "aliti": "al", "alli": "al",
+	"fulness": "ful", "ousli": "ous", "ousness": "ous", "iveness": "ive",
+	"iviti": "ive", "biliti": "ble", "bli": "ble", "ogi": "og", "fulli": "ful",
+	"lessli": "less", "li": "",
+}
+
+func step2En(w []byte, r1 int) []byte {
+	suffixes := make([]string, 0, len(step2En2))
+	for suffix := range step2En2 {
+		suffixes = append(suffixes, suffix)
+	}
+
+	suffix := longestSuffix(w, suffixes)
+	if suffix == "" || len(w)-len(suffix) < r1 {
+		return w
+	}
+
+	stem := w[:len(w)-len(suffix)]
+	switch suffix {
+	case "ogi":
+		if len(stem) == 0 || stem[len(stem)-1] != 'l' {
+			return w
+		}
+	case "li":
+		if len(stem) == 0 || !isLiEndingEn(stem[len(stem)-1]) {
+			return w
+		}
+	}
+
+	return replaceSuffix(w, suffix, step2En2[suffix])
+}
+
+var step3En3 = map[string]string{
+	"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic",
+	"iciti": "ic", "ical": "ic", "ful": "", "ness": "", "ative": "",
+}
+
+func step3En(w []byte, r1, r2 int) []byte {
+	suffixes := make([]string, 0, len(step3En3))
+	for suffix := range step3En3 {
+		suffixes = append(suffixes, suffix)
+	}
+
+	suffix := longestSuffix(w, suffixes)
+	if suffix == "" || len(w)-len(suffix) < r1 {
+		return w
+	}
+
+	if suffix == "ative" && len(w)-len(suffix) < r2 {
+		return w
+	}
+
+	return replaceSuffix(w, suffix, step3En3[suffix])
+}
+
+func step4En(w []byte, r2 int) []byte {
+	suffix := longestSuffix(w, []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+		"ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	})
+	if suffix == "" || len(w)-len(suffix) < r2 {
+		return w
+	}
+
+	if suffix == "ion" {
+		stem := w[:len(w)-len(suffix)]
+		if len(stem) == 0 || (stem[len(stem)-1] != 's' && stem[len(stem)-1] != 't') {
+			return w
+		}
+	}
+
+	return w[:len(w)-len(suffix)]
+}
+
+func step5En(w []byte, r1, r2 int) []byte {
+	n := len(w)
+	switch {
+	case n > 0 && w[n-1] == 'e':
+		if n-1 >= r2 || (n-1 >= r1 && !endsShortSyllableEn(w[:n-1])) {
+			return w[:n-1]
+		}
+	case n > 1 && w[n-1] == 'l' && w[n-2] == 'l':
+		if n-1 >= r2 {
+			return w[:n-1]
+		}
+	}
+
+	return w
+}
@@ -0,0 +1,146 @@
+package book_inventory_system_search
+
+import "strings"
+
+// The ending groups of the Snowball Russian stemmer. Endings of the groups
+// numbered 1 only match when they follow а or я.
+var (
+	perfectiveGerund1Ru = []string{"в", "вши", "вшись"}
+	perfectiveGerund2Ru = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+	adjectiveRu         = []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым",
+		"ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	}
+	participle1Ru = []string{"ем", "нн", "вш", "ющ", "щ"}
+	participle2Ru = []string{"ивш", "ывш", "ующ"}
+	reflexiveRu   = []string{"ся", "сь"}
+	verb1Ru       = []string{
+		"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют",
+		"ны", "ть", "ешь", "нно",
+	}
+	verb2Ru = []string{
+		"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил",
+		"ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт",
+		"ены", "ить", "ыть", "ишь", "ую", "ю",
+	}
+	nounRu = []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией",
+		"ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах",
+		"иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	}
+	superlativeRu  = []string{"ейш", "ейше"}
+	derivationalRu = []string{"ост", "ость"}
+)
+
+// stemRussian implements the Snowball Russian stemmer. The word is expected
+// in lower case with ё replaced by е.
+func stemRussian(word string) string {
+	w := []rune(word)
+	rv, r2 := regionsRu(w)
+	if rv >= len(w) {
+		return word
+	}
+
+	// Step 1: a perfective gerund, or else a reflexive ending followed by
+	// an adjectival, verb or noun ending.
+	if n := suffixRu(w, rv, perfectiveGerund2Ru, perfectiveGerund1Ru); n > 0 {
+		w = w[:len(w)-n]
+	} else {
+		if n = suffixRu(w, rv, reflexiveRu, nil); n > 0 {
+			w = w[:len(w)-n]
+		}
+
+		if n = suffixRu(w, rv, adjectiveRu, nil); n > 0 {
+			w = w[:len(w)-n]
+			if n = suffixRu(w, rv, participle2Ru, participle1Ru); n > 0 {
+				w = w[:len(w)-n]
+			}
+		} else if n = suffixRu(w, rv, verb2Ru, verb1Ru); n > 0 {
+			w = w[:len(w)-n]
+		} else if n = suffixRu(w, rv, nounRu, nil); n > 0 {
+			w = w[:len(w)-n]
+		}
+	}
+
+	// Step 2.
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3: a derivational ending inside R2.
+	if n := suffixRu(w, r2, derivationalRu, nil); n > 0 {
+		w = w[:len(w)-n]
+	}
+
+	// Step 4: remove a superlative ending and undouble н, undouble н or
+	// remove a soft sign.
+	if n := suffixRu(w, rv, superlativeRu, nil); n > 0 {
+		w = w[:len(w)-n]
+		if endsWithDoubleNRu(w, rv) {
+			w = w[:len(w)-1]
+		}
+	} else if endsWithDoubleNRu(w, rv) {
+		w = w[:len(w)-1]
+	} else if len(w) > rv && w[len(w)-1] == 'ь' {
+		w = w[:len(w)-1]
+	}
+
+	return string(w)
+}
+
+// regionsRu returns the start of RV, the region after the first vowel, and of
+// R2, the region after the first non-vowel following a vowel taken twice.
+func regionsRu(w []rune) (int, int) {
+	rv := len(w)
+	for i, c := range w {
+		if isVowelRu(c) {
+			rv = i + 1
+			break
+		}
+	}
+
+	r1 := nextRegionRu(w, 0)
+	return rv, nextRegionRu(w, r1)
+}
+
+func nextRegionRu(w []rune, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowelRu(w[i]) && isVowelRu(w[i-1]) {
+			return i + 1
+		}
+	}
+
+	return len(w)
+}
+
+func endsWithDoubleNRu(w []rune, rv int) bool {
+	return len(w)-2 >= rv && w[len(w)-1] == 'н' && w[len(w)-2] == 'н'
+}
+
+func isVowelRu(c rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", c)
+}
+
+// suffixRu returns the length of the longest of the endings that lies inside
+// the region starting at start, or 0. The afterAYa endings only match when
+// they follow а or я inside the region.
+func suffixRu(w []rune, start int, endings, afterAYa []string) int {
+	word := string(w)
+	found := 0
+	for _, ending := range endings {
+		n := len([]rune(ending))
+		if n > found && len(w)-n >= start && strings.HasSuffix(word, ending) {
+			found = n
+		}
+	}
+
+	for _, ending := range afterAYa {
+		n := len([]rune(ending))
+		if n > found && len(w)-n-1 >= start && strings.HasSuffix(word, ending) &&
+			(w[len(w)-n-1] == 'а' || w[len(w)-n-1] == 'я') {
+			found = n
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,85 @@
+package book_inventory_system_search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStemEnglish(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"a", "a"},
+		{"is", "is"},
+		{"cats", "cat"},
+		{"books", "book"},
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"library", "librari"},
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"running", "run"},
+		{"hopping", "hop"},
+		{"hopeful", "hope"},
+		{"knightly", "knight"},
+		{"happiness", "happi"},
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"generalization", "general"},
+	}
+
+	for _, tt := range tests {
+		if got := stemEnglish(tt.word); got != tt.want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestStemRussian(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"мир", "мир"},
+		{"книга", "книг"},
+		{"книги", "книг"},
+		{"война", "войн"},
+		{"войны", "войн"},
+		{"библиотека", "библиотек"},
+		{"библиотеки", "библиотек"},
+		{"читатель", "читател"},
+		{"читателями", "читател"},
+		{"красивая", "красив"},
+		{"красивый", "красив"},
+		{"бежать", "бежа"},
+		{"бегала", "бега"},
+		{"преступление", "преступлен"},
+		{"наказания", "наказан"},
+	}
+
+	for _, tt := range tests {
+		if got := stemRussian(tt.word); got != tt.want {
+			t.Errorf("stemRussian(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"War and Peace", []string{"war", "peac"}},
+		{"Война и мир, 1869!", []string{"войн", "мир", "1869"}},
+		{"Ёлки и ЕЛКИ", []string{"елк", "елк"}},
+		{"'Tis the books'", []string{"tis", "book"}},
+		{"the, and; и", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package book_inventory_system_search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {},
+	"for": {}, "from": {}, "in": {}, "is": {}, "it": {}, "of": {}, "on": {}, "or": {},
+	"the": {}, "to": {}, "with": {},
+	"а": {}, "без": {}, "в": {}, "во": {}, "да": {}, "для": {}, "до": {}, "же": {},
+	"за": {}, "и": {}, "из": {}, "к": {}, "как": {}, "ко": {}, "ли": {}, "на": {},
+	"не": {}, "но": {}, "о": {}, "об": {}, "от": {}, "по": {}, "при": {}, "с": {},
+	"со": {}, "то": {}, "у": {}, "что": {},
+}
+
+// Tokenize splits the text into lower-case stemmed terms. Words in Cyrillic
+// are stemmed as Russian, the others as English. Stop words are dropped.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if word == "" {
+			continue
+		}
+
+		if _, ok := stopWords[word]; ok {
+			continue
+		}
+
+		terms = append(terms, stem(strings.ReplaceAll(word, "ё", "е")))
+	}
+
+	return terms
+}
+
+func stem(word string) string {
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return stemRussian(word)
+		}
+	}
+
+	return stemEnglish(word)
+}
@@ -0,0 +1,99 @@
+package book_inventory_system_token
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/goccy/go-json"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims are what a token asserts: the user it was issued to and the time
+// it is valid until, both as Unix seconds.
+type Claims struct {
+	UserID     int   `json:"sub"`
+	IssueDate  int64 `json:"iat"`
+	ExpireDate int64 `json:"exp"`
+}
+
+// Signer issues and verifies tokens of the form payload.signature, where
+// payload is the base64url encoded JSON of the claims and signature the
+// base64url encoded HMAC-SHA256 of the payload.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// Sign issues a token to the user, valid for the ttl of the signer from now.
+func (s *Signer) Sign(userID int, now time.Time) (string, Claims, error) {
+	claims := Claims{
+		UserID:     userID,
+		IssueDate:  now.Unix(),
+		ExpireDate: now.Add(s.ttl).Unix(),
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", claims, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), claims, nil
+}
+
+// Verify checks the signature and the expiry of the token and returns its
+// claims.
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	var claims Claims
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims, ErrInvalidToken
+	}
+
+	sum, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sum, s.sign(encoded)) {
+		return claims, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&claims)
+	if err != nil || claims.UserID == 0 {
+		return claims, ErrInvalidToken
+	}
+
+	if now.Unix() >= claims.ExpireDate {
+		return claims, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+func (s *Signer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,89 @@
+package book_inventory_system_token
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signed builds a token with a valid signature around any payload.
+func signed(s *Signer, payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+}
+
+func TestSignVerify(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+	now := time.Unix(1700000000, 0)
+
+	token, claims, err := signer.Sign(123, now)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	want := Claims{UserID: 123, IssueDate: now.Unix(), ExpireDate: now.Add(time.Hour).Unix()}
+	if claims != want {
+		t.Errorf("Sign claims = %+v, want %+v", claims, want)
+	}
+
+	for _, at := range []time.Time{now, now.Add(time.Hour - time.Second)} {
+		claims, err = signer.Verify(token, at)
+		if err != nil {
+			t.Errorf("Verify at %v: %v", at, err)
+		} else if claims != want {
+			t.Errorf("Verify at %v = %+v, want %+v", at, claims, want)
+		}
+	}
+}
+
+func TestVerifyRejected(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+	now := time.Unix(1700000000, 0)
+
+	token, _, err := signer.Sign(123, now)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	forged, _, err := NewSigner([]byte("other secret"), time.Hour).Sign(123, now)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	payload, signature, _ := strings.Cut(token, ".")
+	admin := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":1,"iat":1700000000,"exp":1700003600}`))
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time
+		err   error
+	}{
+		{"expired", token, now.Add(time.Hour), ErrExpiredToken},
+		{"long expired", token, now.Add(24 * time.Hour), ErrExpiredToken},
+		{"other secret", forged, now, ErrInvalidToken},
+		{"altered payload", admin + "." + signature, now, ErrInvalidToken},
+		{"altered signature", payload + "." + signature[:len(signature)-2] + "AA", now, ErrInvalidToken},
+		{"empty", "", now, ErrInvalidToken},
+		{"no signature", payload, now, ErrInvalidToken},
+		{"empty signature", payload + ".", now, ErrInvalidToken},
+		{"signature not base64", payload + ".!!!", now, ErrInvalidToken},
+		{"payload not base64", "!!!." + base64.RawURLEncoding.EncodeToString(signer.sign("!!!")), now, ErrInvalidToken},
+		{"payload not json", signed(signer, "not json"), now, ErrInvalidToken},
+		{"zero user", signed(signer, `{"sub":0,"iat":1700000000,"exp":1700003600}`), now, ErrInvalidToken},
+		{"no user", signed(signer, `{"iat":1700000000,"exp":1700003600}`), now, ErrInvalidToken},
+		{"unknown claim", signed(signer, `{"sub":123,"iat":1700000000,"exp":1700003600,"role":"admin"}`), now, ErrInvalidToken},
+		{"no expiry", signed(signer, `{"sub":123,"iat":1700000000}`), now, ErrExpiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Verify(tt.token, tt.now); !errors.Is(err, tt.err) {
+				t.Errorf("Verify(%q) = %v, want %v", tt.token, err, tt.err)
+			}
+		})
+	}
+}